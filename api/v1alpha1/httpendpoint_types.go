@@ -0,0 +1,127 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HTTPEndpointSpec defines connection settings shared by the HTTP requests referencing the endpoint.
+type HTTPEndpointSpec struct {
+	// Base URL of the endpoint. The path of a referencing request is appended to it.
+	// Example: "https://api.example.com/v1"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.+"
+	BaseURL string `json:"baseUrl"`
+	// HTTP headers included in every request. Headers of a request override headers with the same name.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Authentication for requests that do not set their own authenticationRef.
+	// Secrets of a ClusterHTTPEndpoint must set their namespace.
+	// +optional
+	AuthenticationRef *HTTPAuthenticationRef `json:"authenticationRef,omitempty"`
+	// TLS settings for requests that do not set their own tls.
+	// Secrets and ConfigMaps of a ClusterHTTPEndpoint must set their namespace.
+	// +optional
+	TLS *HTTPTLSSpec `json:"tls,omitempty"`
+	// Timeout of a single request attempt for requests that do not set their own timeout.
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+	// Health check of the endpoint, reflected in the Reachable and Authenticated conditions.
+	// +optional
+	HealthCheck *HTTPEndpointHealthCheckSpec `json:"healthCheck,omitempty"`
+}
+
+// HTTPEndpointHealthCheckSpec defines how the health of an endpoint is checked.
+type HTTPEndpointHealthCheckSpec struct {
+	// Path appended to the base URL for the health check request. Defaults to the base URL itself.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// How often the endpoint is checked. Defaults to "5m".
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +optional
+	Interval string `json:"interval,omitempty"`
+}
+
+// HTTPEndpointStatus defines the observed state of an endpoint.
+type HTTPEndpointStatus struct {
+	// Conditions represent the latest health check of the endpoint:
+	// - Reachable: the endpoint returned an HTTP response
+	// - Authenticated: the credentials were resolved and accepted by the endpoint
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastCheckTime records when the endpoint was last checked.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// ObservedGeneration reflects the generation of the spec that was last checked.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// HTTPEndpointRef references an HTTPEndpoint in the namespace of the resource, or a ClusterHTTPEndpoint.
+type HTTPEndpointRef struct {
+	// Kind of the endpoint. Supported: HTTPEndpoint, ClusterHTTPEndpoint
+	// +kubebuilder:validation:Enum=HTTPEndpoint;ClusterHTTPEndpoint
+	// +kubebuilder:default=HTTPEndpoint
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name of the endpoint.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Base URL",type="string",JSONPath=".spec.baseUrl"
+//+kubebuilder:printcolumn:name="Reachable",type="string",JSONPath=".status.conditions[?(@.type==\"Reachable\")].status"
+//+kubebuilder:printcolumn:name="Authenticated",type="string",JSONPath=".status.conditions[?(@.type==\"Authenticated\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// HTTPEndpoint is the Schema for the httpendpoints API
+type HTTPEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HTTPEndpointSpec   `json:"spec,omitempty"`
+	Status HTTPEndpointStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HTTPEndpointList contains a list of HTTPEndpoint
+type HTTPEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HTTPEndpoint `json:"items"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Base URL",type="string",JSONPath=".spec.baseUrl"
+//+kubebuilder:printcolumn:name="Reachable",type="string",JSONPath=".status.conditions[?(@.type==\"Reachable\")].status"
+//+kubebuilder:printcolumn:name="Authenticated",type="string",JSONPath=".status.conditions[?(@.type==\"Authenticated\")].status"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterHTTPEndpoint is the Schema for the cluster-scoped clusterhttpendpoints API
+type ClusterHTTPEndpoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HTTPEndpointSpec   `json:"spec,omitempty"`
+	Status HTTPEndpointStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterHTTPEndpointList contains a list of ClusterHTTPEndpoint
+type ClusterHTTPEndpointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterHTTPEndpoint `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HTTPEndpoint{}, &HTTPEndpointList{}, &ClusterHTTPEndpoint{}, &ClusterHTTPEndpointList{})
+}
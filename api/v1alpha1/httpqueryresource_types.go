@@ -6,15 +6,18 @@ import (
 )
 
 // HTTPAuthenticationRef defines how to authenticate HTTP requests via a Secret.
+// +kubebuilder:validation:XValidation:rule="self.type != 'serviceAccountToken' || (has(self.audience) && self.audience != '')",message="audience is required for serviceAccountToken"
 type HTTPAuthenticationRef struct {
 	// Name of the Secret containing authentication details.
+	// For serviceAccountToken, the name of the ServiceAccount.
 	// +kubebuilder:validation:Required
 	Name string `json:"name"`
 	// Namespace of the Secret. Defaults to the namespace of the HTTPQueryResource.
+	// ServiceAccounts must be in the namespace of the HTTPQueryResource.
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
-	// Type of authentication. Supported: basic, bearer, apikey, oauth2
-	// +kubebuilder:validation:Enum=basic;bearer;apikey;oauth2
+	// Type of authentication. Supported: basic, bearer, apikey, oauth2, awsSigV4, hmac, serviceAccountToken
+	// +kubebuilder:validation:Enum=basic;bearer;apikey;oauth2;awsSigV4;hmac;serviceAccountToken
 	// +kubebuilder:validation:Required
 	Type string `json:"type"`
 	// Key within the Secret for the username (basic auth). Defaults to "username".
@@ -44,25 +47,389 @@ type HTTPAuthenticationRef struct {
 	// OAuth2 scopes to request (space-separated). Optional.
 	// +optional
 	Scopes string `json:"scopes,omitempty"`
+	// OAuth2 grant type. Defaults to client_credentials.
+	// - client_credentials: authenticates with the client credentials only
+	// - jwt_bearer: sends a JWT assertion signed with the private key (RFC 7523)
+	// - refresh_token: refreshes with the refresh token from the Secret
+	// - token_exchange: exchanges the subject token from the Secret (RFC 8693)
+	// +kubebuilder:validation:Enum=client_credentials;jwt_bearer;refresh_token;token_exchange
+	// +optional
+	GrantType string `json:"grantType,omitempty"`
+	// How the OAuth2 client authenticates at the token endpoint. Defaults to client_secret_basic
+	// when a client secret is present, falling back to client_secret_post if rejected.
+	// private_key_jwt sends a JWT client assertion signed with the private key.
+	// +kubebuilder:validation:Enum=client_secret_basic;client_secret_post;private_key_jwt
+	// +optional
+	ClientAuthentication string `json:"clientAuthentication,omitempty"`
+	// Key within the Secret for the PEM-encoded RSA or ECDSA P-256 private key signing JWT assertions
+	// (jwt_bearer grant and private_key_jwt). Defaults to "privateKey".
+	// +optional
+	PrivateKeyKey string `json:"privateKeyKey,omitempty"`
+	// Key ID sent in the header of JWT assertions.
+	// +optional
+	KeyID string `json:"keyId,omitempty"`
+	// Subject of the JWT bearer assertion (jwt_bearer). Defaults to the client ID.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+	// Key within the Secret for the OAuth2 refresh token (refresh_token). Defaults to "refreshToken".
+	// +optional
+	RefreshTokenKey string `json:"refreshTokenKey,omitempty"`
+	// Write refresh tokens rotated by the token endpoint back to the Secret (refresh_token),
+	// so they survive operator restarts.
+	// +optional
+	WriteBackRefreshToken bool `json:"writeBackRefreshToken,omitempty"`
+	// Key within the Secret for the subject token (token_exchange). Defaults to "subjectToken".
+	// +optional
+	SubjectTokenKey string `json:"subjectTokenKey,omitempty"`
+	// Type of the subject token (token_exchange).
+	// Defaults to "urn:ietf:params:oauth:token-type:access_token".
+	// +optional
+	SubjectTokenType string `json:"subjectTokenType,omitempty"`
+	// Additional parameters sent to the OAuth2 token endpoint, e.g. "resource".
+	// +optional
+	TokenParams map[string]string `json:"tokenParams,omitempty"`
+	// Key within the Secret for the AWS access key ID (awsSigV4). Defaults to "accessKeyId".
+	// +optional
+	AccessKeyIDKey string `json:"accessKeyIdKey,omitempty"`
+	// Key within the Secret for the AWS secret access key (awsSigV4). Defaults to "secretAccessKey".
+	// +optional
+	SecretAccessKeyKey string `json:"secretAccessKeyKey,omitempty"`
+	// Key within the Secret for the optional AWS session token (awsSigV4). Defaults to "sessionToken".
+	// +optional
+	SessionTokenKey string `json:"sessionTokenKey,omitempty"`
+	// AWS region of the endpoint, e.g. "us-east-1". Required for awsSigV4.
+	// +optional
+	Region string `json:"region,omitempty"`
+	// AWS service name used for signing, e.g. "execute-api" or "s3". Required for awsSigV4.
+	// +optional
+	Service string `json:"service,omitempty"`
+	// Audience of the token. Required for serviceAccountToken, and must not be the audience of
+	// the API server. For oauth2, sent as the audience parameter of the token request.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+	// Requested lifetime of the token in seconds (serviceAccountToken). Defaults to 3600.
+	// +kubebuilder:validation:Minimum=600
+	// +optional
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+	// HMAC signing settings (hmac). Defaults are used when unset.
+	// +optional
+	HMAC *HTTPHMACSpec `json:"hmac,omitempty"`
 }
 
-// HTTPSpec defines the HTTP request details.
-type HTTPSpec struct {
-	// URL for the HTTP request.
+// HTTPHMACSpec defines how requests are signed with an HMAC over a canonical string.
+type HTTPHMACSpec struct {
+	// Key within the Secret for the shared secret. Defaults to "secret".
+	// +optional
+	SecretKey string `json:"secretKey,omitempty"`
+	// Digest algorithm of the HMAC. Defaults to sha256.
+	// +kubebuilder:validation:Enum=sha256;sha512;sha1
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+	// Encoding of the signature. Defaults to hex.
+	// +kubebuilder:validation:Enum=hex;base64
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+	// Go template rendering the signed string. Receives .Method, .Host, .Path, .Query, .Timestamp and .Body.
+	// Defaults to "{{ .Method }}\n{{ .Path }}\n{{ .Timestamp }}\n{{ .Body }}".
+	// +optional
+	CanonicalTemplate string `json:"canonicalTemplate,omitempty"`
+	// Header receiving the signature. Defaults to "X-Signature".
+	// +optional
+	SignatureHeader string `json:"signatureHeader,omitempty"`
+	// Prefix of the signature header value, e.g. "sha256=".
+	// +optional
+	SignaturePrefix string `json:"signaturePrefix,omitempty"`
+	// Header receiving the timestamp. Defaults to "X-Timestamp".
+	// +optional
+	TimestampHeader string `json:"timestampHeader,omitempty"`
+	// Format of the timestamp. Defaults to unix (seconds).
+	// +kubebuilder:validation:Enum=unix;unixMilli;rfc3339
+	// +optional
+	TimestampFormat string `json:"timestampFormat,omitempty"`
+}
+
+// HTTPPaginationSpec defines how to follow paginated API responses.
+type HTTPPaginationSpec struct {
+	// Type of pagination. Supported: link, cursor, offset, page
+	// - link: follows the URL in the Link response header with rel="next"
+	// - cursor: reads the next cursor from the response body and sends it as a query parameter
+	// - offset: increments an offset query parameter by the number of items received
+	// - page: increments a page number query parameter
+	// +kubebuilder:validation:Enum=link;cursor;offset;page
+	// +kubebuilder:validation:Required
+	Type string `json:"type"`
+	// JSONPath expression to the next cursor in the response body (cursor pagination).
+	// Pagination stops when the cursor is missing or empty.
+	// Example: "meta.next_cursor"
+	// +optional
+	CursorPath string `json:"cursorPath,omitempty"`
+	// Query parameter used to send the cursor. Defaults to "cursor".
+	// +optional
+	CursorParam string `json:"cursorParam,omitempty"`
+	// Query parameter used to send the offset. Defaults to "offset".
+	// +optional
+	OffsetParam string `json:"offsetParam,omitempty"`
+	// Query parameter used to send the page number. Defaults to "page".
+	// +optional
+	PageParam string `json:"pageParam,omitempty"`
+	// First page number (page pagination). Defaults to 1.
+	// +optional
+	StartPage *int `json:"startPage,omitempty"`
+	// Query parameter used to send the page size. Defaults to "limit".
+	// +optional
+	LimitParam string `json:"limitParam,omitempty"`
+	// Page size to request (offset and page pagination). When set, a page with fewer
+	// items than the limit is treated as the last page.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Limit int `json:"limit,omitempty"`
+	// Maximum number of pages to fetch. The request fails if more pages are available,
+	// so that resources beyond the cap are never pruned. Defaults to 100.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPages int `json:"maxPages,omitempty"`
+}
+
+// HTTPRetrySpec defines how failed HTTP requests are retried.
+type HTTPRetrySpec struct {
+	// Maximum number of attempts, including the first one. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts int `json:"maxAttempts,omitempty"`
+	// Backoff before the first retry. Doubles after every attempt. Defaults to "1s".
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +optional
+	BaseBackoff string `json:"baseBackoff,omitempty"`
+	// Upper bound for the backoff between attempts. Defaults to "30s".
+	// A Retry-After header asking for a longer wait stops retrying.
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +optional
+	MaxBackoff string `json:"maxBackoff,omitempty"`
+	// Random jitter added to the backoff, as a percentage of the backoff. Defaults to 20.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	JitterPercent *int `json:"jitterPercent,omitempty"`
+	// HTTP status codes that are retried. Defaults to 429, 502, 503 and 504.
+	// Network errors are always retried. 429 and 503 responses honour the Retry-After header.
+	// +optional
+	RetryableStatusCodes []int `json:"retryableStatusCodes,omitempty"`
+}
+
+// HTTPTLSCARef references PEM-encoded CA certificates in a Secret or ConfigMap.
+type HTTPTLSCARef struct {
+	// Kind of the referenced object. Supported: Secret, ConfigMap
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	// +kubebuilder:default=ConfigMap
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Name of the Secret or ConfigMap.
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Pattern="^https?://.+"
+	Name string `json:"name"`
+	// Namespace of the Secret or ConfigMap. Defaults to the namespace of the HTTPQueryResource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Key holding the CA bundle. Defaults to "ca.crt".
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// HTTPTLSClientCertRef references a kubernetes.io/tls Secret with a client certificate and key.
+type HTTPTLSClientCertRef struct {
+	// Name of the Secret. The certificate and key are read from "tls.crt" and "tls.key".
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace of the Secret. Defaults to the namespace of the HTTPQueryResource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// HTTPTLSSpec defines TLS settings for HTTP requests.
+type HTTPTLSSpec struct {
+	// CA certificates used to verify the server, in addition to the system roots.
+	// +optional
+	CARef *HTTPTLSCARef `json:"caRef,omitempty"`
+	// Client certificate and key presented to the server (mutual TLS).
+	// +optional
+	ClientCertRef *HTTPTLSClientCertRef `json:"clientCertRef,omitempty"`
+	// Server name used to verify the server certificate. Defaults to the host of the URL.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+	// InsecureSkipVerify disables server certificate verification.
+	// INSECURE: this makes requests vulnerable to man-in-the-middle attacks. Use for testing only.
+	// +optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// HTTPKeyRef references a key in a Secret or ConfigMap.
+type HTTPKeyRef struct {
+	// Name of the Secret or ConfigMap.
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Namespace of the Secret or ConfigMap. Defaults to the namespace of the HTTPQueryResource.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Key within the Secret or ConfigMap.
+	// +kubebuilder:validation:Required
+	Key string `json:"key"`
+}
+
+// HTTPTemplateValue defines a named value exposed to request templates as .Values.<name>.
+// Exactly one of value, secretKeyRef and configMapKeyRef should be set.
+type HTTPTemplateValue struct {
+	// Name of the value.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[a-zA-Z_][a-zA-Z0-9_]*$"
+	Name string `json:"name"`
+	// Literal value.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// Value read from a key of a Secret.
+	// +optional
+	SecretKeyRef *HTTPKeyRef `json:"secretKeyRef,omitempty"`
+	// Value read from a key of a ConfigMap.
+	// +optional
+	ConfigMapKeyRef *HTTPKeyRef `json:"configMapKeyRef,omitempty"`
+}
+
+// HTTPStreamSpec defines how the events of a change feed are folded into the item set.
+type HTTPStreamSpec struct {
+	// Format of the stream. Supported: sse (Server-Sent Events), ndjson (a JSON event per line)
+	// +kubebuilder:validation:Enum=sse;ndjson
+	// +kubebuilder:default=sse
+	// +optional
+	Format string `json:"format,omitempty"`
+	// JSONPath expression to the ID of an item, which identifies the item updated or deleted by an event.
+	// Example: "id"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	KeyField string `json:"keyField"`
+	// JSONPath expression to the type of an event in its data. Defaults to "type" for ndjson.
+	// For sse, the event field is used when the data has no type.
+	// +optional
+	TypeField string `json:"typeField,omitempty"`
+	// JSONPath expression to the item in the data of an event. Defaults to the data itself.
+	// Example: "object"
+	// +optional
+	DataPath string `json:"dataPath,omitempty"`
+	// Event types that delete the item. All other events insert or update the item.
+	// Defaults to ["delete"].
+	// +optional
+	DeleteTypes []string `json:"deleteTypes,omitempty"`
+	// Event types marking that the server has sent the current state of every item, e.g. after
+	// connecting. Resources are only pruned once such an event has been received.
+	// +optional
+	SnapshotTypes []string `json:"snapshotTypes,omitempty"`
+	// Changes are collected for this long before the item set is reconciled. Defaults to "1s".
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +optional
+	Debounce string `json:"debounce,omitempty"`
+}
+
+// HTTPItemDetailSpec defines a follow-up request made for every item of the list response.
+// It uses the authentication, TLS, retry and timeout settings of the list request.
+type HTTPItemDetailSpec struct {
+	// URL of the detail request. Go template receiving the list item as .Item and its index as .Index.
+	// Example: "https://api.example.com/things/{{ .Item.id }}"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
 	URL string `json:"url"`
 	// HTTP method. Defaults to GET.
 	// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;DELETE
 	// +kubebuilder:default=GET
 	// +optional
 	Method string `json:"method,omitempty"`
-	// HTTP headers to include in the request.
+	// HTTP headers to include in the request. Values can be Go templates.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+	// Request body. Can be a Go template.
+	// +optional
+	Body string `json:"body,omitempty"`
+	// JSONPath expression to the detail object in the response. Defaults to the root.
+	// +optional
+	ResponsePath string `json:"responsePath,omitempty"`
+	// Merge determines how the detail is combined with the list item. Supported: merge, replace
+	// - merge: fields of the detail are added to the item, overwriting fields with the same name
+	// - replace: the detail replaces the item
+	// +kubebuilder:validation:Enum=merge;replace
+	// +kubebuilder:default=merge
+	// +optional
+	Merge string `json:"merge,omitempty"`
+	// Maximum number of concurrent detail requests. Defaults to 4.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// HTTPGraphQLPaginationSpec defines how a cursor-based GraphQL connection is followed.
+type HTTPGraphQLPaginationSpec struct {
+	// JSONPath expression to the connection object, whose pageInfo holds hasNextPage and endCursor.
+	// Example: "data.repository.issues"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ConnectionPath string `json:"connectionPath"`
+	// Variable receiving the endCursor of the previous page. Defaults to "after".
+	// +optional
+	CursorVariable string `json:"cursorVariable,omitempty"`
+	// Maximum number of pages to fetch. Defaults to 100.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPages int `json:"maxPages,omitempty"`
+}
+
+// HTTPGraphQLSpec defines a GraphQL query, sent as the JSON body of a POST request.
+type HTTPGraphQLSpec struct {
+	// GraphQL query document.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+	// Variables of the query as a JSON object. Can be a Go template with the same context as the URL.
+	// Example: '{"team": "{{ .Values.team }}"}'
+	// +optional
+	Variables string `json:"variables,omitempty"`
+	// Name of the operation to execute, if the query contains several operations.
+	// +optional
+	OperationName string `json:"operationName,omitempty"`
+	// Pagination follows a cursor-based connection. When set, all pages are fetched and merged before templating.
+	// +optional
+	Pagination *HTTPGraphQLPaginationSpec `json:"pagination,omitempty"`
+}
+
+// HTTPStatusCodeAction defines how a response with a declared status code is treated.
+// +kubebuilder:validation:Enum=empty;unchanged;error
+type HTTPStatusCodeAction string
+
+// HTTPSpec defines the HTTP request details.
+// +kubebuilder:validation:XValidation:rule="has(self.url) != has(self.endpointRef)",message="exactly one of url and endpointRef must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.transform) || !has(self.stream)",message="transform cannot be combined with stream"
+type HTTPSpec struct {
+	// URL for the HTTP request. Can be a Go template. Required unless endpointRef is set.
+	// +kubebuilder:validation:Pattern="^(https?://|\\{\\{).+"
+	// +optional
+	URL string `json:"url,omitempty"`
+	// Endpoint providing the base URL and default headers, authentication, TLS and timeout.
+	// Settings of the request override the endpoint defaults.
+	// +optional
+	EndpointRef *HTTPEndpointRef `json:"endpointRef,omitempty"`
+	// Path appended to the base URL of the endpoint, e.g. "/users?active=true". Can be a Go template.
+	// Only used with endpointRef.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// HTTP method. Defaults to GET.
+	// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;DELETE
+	// +kubebuilder:default=GET
+	// +optional
+	Method string `json:"method,omitempty"`
+	// HTTP headers to include in the request. Values can be Go templates.
 	// +optional
 	Headers map[string]string `json:"headers,omitempty"`
 	// Request body for POST/PUT/PATCH requests. Can be a Go template.
 	// +optional
 	Body string `json:"body,omitempty"`
+	// Values exposed to the URL, header and body templates as .Values.<name>.
+	// +optional
+	Values []HTTPTemplateValue `json:"values,omitempty"`
 	// Authentication details.
 	// +optional
 	AuthenticationRef *HTTPAuthenticationRef `json:"authenticationRef,omitempty"`
@@ -71,6 +438,92 @@ type HTTPSpec struct {
 	// Example: "$.data" if response is {"data": [...]}
 	// +optional
 	ResponsePath string `json:"responsePath,omitempty"`
+	// Format of the response body. Detected from the Content-Type header when unset, defaulting to json.
+	// Other formats are converted to JSON before the response path is applied.
+	// +kubebuilder:validation:Enum=json;yaml;xml;csv;ndjson
+	// +optional
+	ResponseFormat string `json:"responseFormat,omitempty"`
+	// Transform is a jq program applied to every response, after it is converted to JSON and
+	// before the response path is applied. A program producing several values, like ".users[]",
+	// yields an array. Not supported with stream.
+	// Example: "[.users[] | {id, name: .profile.name}]"
+	// +optional
+	Transform string `json:"transform,omitempty"`
+	// Filter is a CEL expression selecting the items that become resources. The item is exposed
+	// as "item", and items for which the expression is false are dropped before templating.
+	// Example: "item.status == 'active' && item.region in ['eu']"
+	// +optional
+	Filter string `json:"filter,omitempty"`
+	// StatusCodeHandling declares how responses with the given status codes are treated, instead of
+	// failing on codes outside 2xx. Keys are status codes, e.g. "404".
+	// - empty: the response has no items, so resources are pruned if prune is enabled
+	// - unchanged: the current resources are kept without an error (not supported by sources)
+	// - error: the request fails, also for 2xx codes
+	// +kubebuilder:validation:XValidation:rule="self.all(code, code.matches('^[1-5][0-9][0-9]$'))",message="keys must be HTTP status codes"
+	// +optional
+	StatusCodeHandling map[string]HTTPStatusCodeAction `json:"statusCodeHandling,omitempty"`
+	// Pagination details. When set, all pages are fetched and merged before templating.
+	// +optional
+	Pagination *HTTPPaginationSpec `json:"pagination,omitempty"`
+	// GraphQL sends a GraphQL query instead of the body. The method is always POST, and a
+	// response with errors fails the poll.
+	// +optional
+	GraphQL *HTTPGraphQLSpec `json:"graphql,omitempty"`
+	// ItemDetail defines a follow-up request made for every item, whose response is combined
+	// with the item before templating.
+	// +optional
+	ItemDetail *HTTPItemDetailSpec `json:"itemDetail,omitempty"`
+	// Stream holds a long-lived connection to a change feed instead of polling. Upsert and delete
+	// events are folded into the item set, which is reconciled when it changes.
+	// Only supported by http, and not combined with pagination, graphql or itemDetail.
+	// +optional
+	Stream *HTTPStreamSpec `json:"stream,omitempty"`
+	// Retry policy for failed requests. Requests are not retried when unset.
+	// +optional
+	Retry *HTTPRetrySpec `json:"retry,omitempty"`
+	// TLS settings for the request.
+	// +optional
+	TLS *HTTPTLSSpec `json:"tls,omitempty"`
+	// Timeout of a single request attempt, including reading the response.
+	// Defaults to the operator-wide --http-timeout.
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+	// Maximum size of the response body in bytes. Larger responses fail the poll.
+	// Defaults to the operator-wide --http-max-response-bytes.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxResponseBytes int64 `json:"maxResponseBytes,omitempty"`
+}
+
+// HTTPSourceJoinSpec defines how the items of a source are matched to the primary items.
+type HTTPSourceJoinSpec struct {
+	// JSONPath expression to the key in a primary item.
+	// Example: "id"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ItemKey string `json:"itemKey"`
+	// JSONPath expression to the key in an item of the source.
+	// Example: "tenantId"
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SourceKey string `json:"sourceKey"`
+}
+
+// HTTPSourceSpec defines an additional named HTTP request whose items are exposed to the template.
+// +kubebuilder:validation:XValidation:rule="!has(self.statusCodeHandling) || self.statusCodeHandling.all(code, self.statusCodeHandling[code] != 'unchanged')",message="statusCodeHandling of sources cannot be unchanged"
+type HTTPSourceSpec struct {
+	// Name of the source. The items are exposed to the template as .Sources.<name>.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^[A-Za-z_][A-Za-z0-9_]*$"
+	Name string `json:"name"`
+
+	HTTPSpec `json:",inline"`
+
+	// Join matches the items of the source to every primary item. The matched items are
+	// exposed to the template as .Joined.<name>.
+	// +optional
+	Join *HTTPSourceJoinSpec `json:"join,omitempty"`
 }
 
 // HTTPStatusUpdateSpec defines how to update status via HTTP requests.
@@ -92,10 +545,28 @@ type HTTPStatusUpdateSpec struct {
 	// Authentication details for status updates.
 	// +optional
 	AuthenticationRef *HTTPAuthenticationRef `json:"authenticationRef,omitempty"`
+	// Retry policy for failed status update requests. Requests are not retried when unset.
+	// +optional
+	Retry *HTTPRetrySpec `json:"retry,omitempty"`
+	// TLS settings for status update requests.
+	// +optional
+	TLS *HTTPTLSSpec `json:"tls,omitempty"`
+	// Timeout of a single status update request attempt.
+	// Defaults to the operator-wide --http-timeout.
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+	// Maximum number of bytes read from an error response of a status update, which is
+	// included in the error message. Successful responses are not read.
+	// Defaults to the operator-wide --http-max-response-bytes.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxResponseBytes int64 `json:"maxResponseBytes,omitempty"`
 }
 
 // HTTPQueryResourceSpec defines the desired state of HTTPQueryResource
 // +kubebuilder:deepcopy-gen=true
+// +kubebuilder:validation:XValidation:rule="!has(self.aggregateTemplate) || !has(self.templateMode) || self.templateMode != 'aggregate'",message="aggregateTemplate requires templateMode perItem"
 type HTTPQueryResourceSpec struct {
 	// PollInterval defines how often to make the HTTP request and reconcile resources.
 	// Format is a duration string like "5m", "1h", "30s".
@@ -107,6 +578,13 @@ type HTTPQueryResourceSpec struct {
 	// +kubebuilder:validation:Required
 	HTTP HTTPSpec `json:"http"`
 
+	// Sources defines additional HTTP requests made on every poll. Each source is requested
+	// once and its items are exposed to the template, optionally joined to the primary items.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Sources []HTTPSourceSpec `json:"sources,omitempty"`
+
 	// Go template string for the Kubernetes resource to be created for each item.
 	// The template will receive a map[string]interface{} named `Item` representing the JSON object.
 	// Field names are the keys in the map.
@@ -114,15 +592,130 @@ type HTTPQueryResourceSpec struct {
 	// +kubebuilder:validation:MinLength=1
 	Template string `json:"template"`
 
+	// TemplateMode determines how the template is rendered. "perItem" renders it for every item,
+	// with the item as `.Item` and its index as `.Index`. "aggregate" renders it once, with all
+	// items as `.Items`, the decoded response as `.Response` and the response headers as `.Headers`.
+	// +optional
+	// +kubebuilder:validation:Enum=perItem;aggregate
+	// +kubebuilder:default=perItem
+	TemplateMode string `json:"templateMode,omitempty"`
+
+	// AggregateTemplate is rendered once, like the template in aggregate mode, in addition to
+	// the per-item template.
+	// +optional
+	AggregateTemplate string `json:"aggregateTemplate,omitempty"`
+
+	// ItemKey is the JSONPath to a stable identifier of an item, e.g. "id". The key is exposed to
+	// the template as `.ItemKey`, and the resources of every item are labeled konnektr.io/item-key.
+	// A resource keeps its name while its item exists, even if the rendered name changes, and is
+	// pruned once the item is gone. Items without a unique key fail to process.
+	// +optional
+	ItemKey string `json:"itemKey,omitempty"`
+
 	// Prune determines if resources previously created by this CR but no longer corresponding
 	// to an item in the latest HTTP response should be deleted. Defaults to true.
 	// +optional
 	// +kubebuilder:default=true
 	Prune *bool `json:"prune,omitempty"`
 
+	// FailurePolicy determines what happens to the resources of items that fail to render.
+	// "prune" prunes them like the resources of removed items. "keepExisting" keeps the existing
+	// resources of the failed items, or all existing resources while any item fails if itemKey
+	// is not set. Defaults to "prune".
+	// +optional
+	// +kubebuilder:validation:Enum=prune;keepExisting
+	// +kubebuilder:default=prune
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+
+	// SkipUnchanged skips templating and applying resources when the poll response is unchanged
+	// since the last reconciliation, see status.lastResponse. Only enable it if the resources
+	// depend on the response alone: changes of values, lookup, configMapValue and secretValue
+	// are not detected. Changes of managed resources always trigger a full reconciliation.
+	// +optional
+	SkipUnchanged bool `json:"skipUnchanged,omitempty"`
+
 	// StatusUpdate defines how to update status via HTTP requests.
 	// +kubebuilder:validation:Optional
 	StatusUpdate *HTTPStatusUpdateSpec `json:"statusUpdate,omitempty"`
+
+	// Webhook enables push mode: authenticated calls to the webhook path of this resource
+	// reconcile it immediately. The poll interval then serves as a fallback resync.
+	// Requires the operator to run with --webhook-bind-address.
+	// +optional
+	Webhook *HTTPWebhookSpec `json:"webhook,omitempty"`
+}
+
+// HTTPWebhookSpec defines how webhook calls that trigger a reconciliation are authenticated.
+type HTTPWebhookSpec struct {
+	// How webhook calls are authenticated. Defaults to hmac.
+	// - hmac: the header carries an HMAC of the timestamp and request body, keyed with the shared secret
+	// - token: the header carries the shared secret itself
+	// +kubebuilder:validation:Enum=hmac;token
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Key of the Secret holding the shared secret.
+	// +kubebuilder:validation:Required
+	SecretRef HTTPKeyRef `json:"secretRef"`
+	// Header carrying the signature or token. Defaults to "X-Signature" for hmac and
+	// "Authorization" for token, where the token may be sent as "Bearer <token>".
+	// +optional
+	Header string `json:"header,omitempty"`
+	// Prefix of the signature, e.g. "sha256=" for GitHub-style signatures (hmac).
+	// +optional
+	SignaturePrefix string `json:"signaturePrefix,omitempty"`
+	// Digest algorithm of the HMAC. Defaults to sha256.
+	// +kubebuilder:validation:Enum=sha256;sha512;sha1
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+	// Encoding of the signature. Defaults to hex.
+	// +kubebuilder:validation:Enum=hex;base64
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+	// Header carrying the time of the call in Unix seconds (hmac). The signature covers
+	// "<timestamp>.<body>", so that captured calls cannot be replayed. Defaults to "X-Timestamp".
+	// +optional
+	TimestampHeader string `json:"timestampHeader,omitempty"`
+	// Maximum difference between the timestamp of a call and the time it is received (hmac).
+	// Defaults to "5m".
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// +optional
+	TimestampTolerance string `json:"timestampTolerance,omitempty"`
+	// InsecureSkipTimestamp verifies the signature of the body alone, for senders that do not
+	// sign a timestamp, such as GitHub (hmac). Captured calls can then be replayed.
+	// +optional
+	InsecureSkipTimestamp bool `json:"insecureSkipTimestamp,omitempty"`
+	// UsePayload uses the request body of a webhook call as the response of the http request,
+	// so that no poll is needed. The body is decoded with the responseFormat and responsePath of
+	// http and must contain all items. Sources are still requested.
+	// +optional
+	UsePayload bool `json:"usePayload,omitempty"`
+}
+
+// HTTPRequestStatus records the attempts made for the HTTP requests of the last reconciliation.
+type HTTPRequestStatus struct {
+	// PollAttempts is the number of attempts made for the poll request, including retries.
+	// +optional
+	PollAttempts int32 `json:"pollAttempts,omitempty"`
+	// StatusUpdateRetries is the number of retries performed for status update callbacks.
+	// +optional
+	StatusUpdateRetries int32 `json:"statusUpdateRetries,omitempty"`
+	// LastRetryReason describes why the most recent retry was performed.
+	// +optional
+	LastRetryReason string `json:"lastRetryReason,omitempty"`
+}
+
+// HTTPResponseValidators identify the last successfully processed poll response.
+type HTTPResponseValidators struct {
+	// ETag of the response, sent as If-None-Match on the next poll.
+	// +optional
+	ETag string `json:"etag,omitempty"`
+	// LastModified of the response, sent as If-Modified-Since on the next poll.
+	// +optional
+	LastModified string `json:"lastModified,omitempty"`
+	// BodyHash is the SHA-256 hash of the response body, used to detect unchanged responses
+	// when the server does not support conditional requests.
+	// +optional
+	BodyHash string `json:"bodyHash,omitempty"`
 }
 
 // HTTPQueryResourceStatus defines the observed state of HTTPQueryResource
@@ -143,6 +736,23 @@ type HTTPQueryResourceStatus struct {
 	// ObservedGeneration reflects the generation of the CR spec that was last processed.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Requests records the attempts made for the HTTP requests of the last reconciliation.
+	// +optional
+	Requests *HTTPRequestStatus `json:"requests,omitempty"`
+
+	// LastResponse identifies the last successfully processed poll response. With skipUnchanged,
+	// unchanged responses skip templating and applying resources.
+	// +optional
+	LastResponse *HTTPResponseValidators `json:"lastResponse,omitempty"`
+
+	// WebhookPath is the path of the webhook listener that triggers a reconciliation of this resource.
+	// +optional
+	WebhookPath string `json:"webhookPath,omitempty"`
+
+	// LastWebhookTime records when the last webhook call was received.
+	// +optional
+	LastWebhookTime *metav1.Time `json:"lastWebhookTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -9,9 +9,85 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHTTPEndpoint) DeepCopyInto(out *ClusterHTTPEndpoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHTTPEndpoint.
+func (in *ClusterHTTPEndpoint) DeepCopy() *ClusterHTTPEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHTTPEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterHTTPEndpoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHTTPEndpointList) DeepCopyInto(out *ClusterHTTPEndpointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterHTTPEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHTTPEndpointList.
+func (in *ClusterHTTPEndpointList) DeepCopy() *ClusterHTTPEndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHTTPEndpointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterHTTPEndpointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPAuthenticationRef) DeepCopyInto(out *HTTPAuthenticationRef) {
 	*out = *in
+	if in.TokenParams != nil {
+		in, out := &in.TokenParams, &out.TokenParams
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpirationSeconds != nil {
+		in, out := &in.ExpirationSeconds, &out.ExpirationSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.HMAC != nil {
+		in, out := &in.HMAC, &out.HMAC
+		*out = new(HTTPHMACSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPAuthenticationRef.
@@ -24,6 +100,265 @@ func (in *HTTPAuthenticationRef) DeepCopy() *HTTPAuthenticationRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPEndpoint) DeepCopyInto(out *HTTPEndpoint) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPEndpoint.
+func (in *HTTPEndpoint) DeepCopy() *HTTPEndpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPEndpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HTTPEndpoint) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPEndpointHealthCheckSpec) DeepCopyInto(out *HTTPEndpointHealthCheckSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPEndpointHealthCheckSpec.
+func (in *HTTPEndpointHealthCheckSpec) DeepCopy() *HTTPEndpointHealthCheckSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPEndpointHealthCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPEndpointList) DeepCopyInto(out *HTTPEndpointList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HTTPEndpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPEndpointList.
+func (in *HTTPEndpointList) DeepCopy() *HTTPEndpointList {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPEndpointList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HTTPEndpointList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPEndpointRef) DeepCopyInto(out *HTTPEndpointRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPEndpointRef.
+func (in *HTTPEndpointRef) DeepCopy() *HTTPEndpointRef {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPEndpointRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPEndpointSpec) DeepCopyInto(out *HTTPEndpointSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.AuthenticationRef != nil {
+		in, out := &in.AuthenticationRef, &out.AuthenticationRef
+		*out = new(HTTPAuthenticationRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(HTTPTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(HTTPEndpointHealthCheckSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPEndpointSpec.
+func (in *HTTPEndpointSpec) DeepCopy() *HTTPEndpointSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPEndpointSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPEndpointStatus) DeepCopyInto(out *HTTPEndpointStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPEndpointStatus.
+func (in *HTTPEndpointStatus) DeepCopy() *HTTPEndpointStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPEndpointStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPGraphQLPaginationSpec) DeepCopyInto(out *HTTPGraphQLPaginationSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGraphQLPaginationSpec.
+func (in *HTTPGraphQLPaginationSpec) DeepCopy() *HTTPGraphQLPaginationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPGraphQLPaginationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPGraphQLSpec) DeepCopyInto(out *HTTPGraphQLSpec) {
+	*out = *in
+	if in.Pagination != nil {
+		in, out := &in.Pagination, &out.Pagination
+		*out = new(HTTPGraphQLPaginationSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPGraphQLSpec.
+func (in *HTTPGraphQLSpec) DeepCopy() *HTTPGraphQLSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPGraphQLSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPHMACSpec) DeepCopyInto(out *HTTPHMACSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPHMACSpec.
+func (in *HTTPHMACSpec) DeepCopy() *HTTPHMACSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPHMACSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPItemDetailSpec) DeepCopyInto(out *HTTPItemDetailSpec) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPItemDetailSpec.
+func (in *HTTPItemDetailSpec) DeepCopy() *HTTPItemDetailSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPItemDetailSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPKeyRef) DeepCopyInto(out *HTTPKeyRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPKeyRef.
+func (in *HTTPKeyRef) DeepCopy() *HTTPKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPPaginationSpec) DeepCopyInto(out *HTTPPaginationSpec) {
+	*out = *in
+	if in.StartPage != nil {
+		in, out := &in.StartPage, &out.StartPage
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPPaginationSpec.
+func (in *HTTPPaginationSpec) DeepCopy() *HTTPPaginationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPPaginationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPQueryResource) DeepCopyInto(out *HTTPQueryResource) {
 	*out = *in
@@ -87,6 +422,13 @@ func (in *HTTPQueryResourceList) DeepCopyObject() runtime.Object {
 func (in *HTTPQueryResourceSpec) DeepCopyInto(out *HTTPQueryResourceSpec) {
 	*out = *in
 	in.HTTP.DeepCopyInto(&out.HTTP)
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]HTTPSourceSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Prune != nil {
 		in, out := &in.Prune, &out.Prune
 		*out = new(bool)
@@ -97,6 +439,11 @@ func (in *HTTPQueryResourceSpec) DeepCopyInto(out *HTTPQueryResourceSpec) {
 		*out = new(HTTPStatusUpdateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(HTTPWebhookSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPQueryResourceSpec.
@@ -128,6 +475,20 @@ func (in *HTTPQueryResourceStatus) DeepCopyInto(out *HTTPQueryResourceStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = new(HTTPRequestStatus)
+		**out = **in
+	}
+	if in.LastResponse != nil {
+		in, out := &in.LastResponse, &out.LastResponse
+		*out = new(HTTPResponseValidators)
+		**out = **in
+	}
+	if in.LastWebhookTime != nil {
+		in, out := &in.LastWebhookTime, &out.LastWebhookTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPQueryResourceStatus.
@@ -140,9 +501,105 @@ func (in *HTTPQueryResourceStatus) DeepCopy() *HTTPQueryResourceStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRequestStatus) DeepCopyInto(out *HTTPRequestStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRequestStatus.
+func (in *HTTPRequestStatus) DeepCopy() *HTTPRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPResponseValidators) DeepCopyInto(out *HTTPResponseValidators) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPResponseValidators.
+func (in *HTTPResponseValidators) DeepCopy() *HTTPResponseValidators {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPResponseValidators)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRetrySpec) DeepCopyInto(out *HTTPRetrySpec) {
+	*out = *in
+	if in.JitterPercent != nil {
+		in, out := &in.JitterPercent, &out.JitterPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.RetryableStatusCodes != nil {
+		in, out := &in.RetryableStatusCodes, &out.RetryableStatusCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRetrySpec.
+func (in *HTTPRetrySpec) DeepCopy() *HTTPRetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSourceJoinSpec) DeepCopyInto(out *HTTPSourceJoinSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSourceJoinSpec.
+func (in *HTTPSourceJoinSpec) DeepCopy() *HTTPSourceJoinSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSourceJoinSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPSourceSpec) DeepCopyInto(out *HTTPSourceSpec) {
+	*out = *in
+	in.HTTPSpec.DeepCopyInto(&out.HTTPSpec)
+	if in.Join != nil {
+		in, out := &in.Join, &out.Join
+		*out = new(HTTPSourceJoinSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSourceSpec.
+func (in *HTTPSourceSpec) DeepCopy() *HTTPSourceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPSourceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPSpec) DeepCopyInto(out *HTTPSpec) {
 	*out = *in
+	if in.EndpointRef != nil {
+		in, out := &in.EndpointRef, &out.EndpointRef
+		*out = new(HTTPEndpointRef)
+		**out = **in
+	}
 	if in.Headers != nil {
 		in, out := &in.Headers, &out.Headers
 		*out = make(map[string]string, len(*in))
@@ -150,10 +607,54 @@ func (in *HTTPSpec) DeepCopyInto(out *HTTPSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]HTTPTemplateValue, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.AuthenticationRef != nil {
 		in, out := &in.AuthenticationRef, &out.AuthenticationRef
 		*out = new(HTTPAuthenticationRef)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatusCodeHandling != nil {
+		in, out := &in.StatusCodeHandling, &out.StatusCodeHandling
+		*out = make(map[string]HTTPStatusCodeAction, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Pagination != nil {
+		in, out := &in.Pagination, &out.Pagination
+		*out = new(HTTPPaginationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GraphQL != nil {
+		in, out := &in.GraphQL, &out.GraphQL
+		*out = new(HTTPGraphQLSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ItemDetail != nil {
+		in, out := &in.ItemDetail, &out.ItemDetail
+		*out = new(HTTPItemDetailSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Stream != nil {
+		in, out := &in.Stream, &out.Stream
+		*out = new(HTTPStreamSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(HTTPRetrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(HTTPTLSSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -180,7 +681,17 @@ func (in *HTTPStatusUpdateSpec) DeepCopyInto(out *HTTPStatusUpdateSpec) {
 	if in.AuthenticationRef != nil {
 		in, out := &in.AuthenticationRef, &out.AuthenticationRef
 		*out = new(HTTPAuthenticationRef)
-		**out = **in
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(HTTPRetrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(HTTPTLSSpec)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -193,3 +704,124 @@ func (in *HTTPStatusUpdateSpec) DeepCopy() *HTTPStatusUpdateSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPStreamSpec) DeepCopyInto(out *HTTPStreamSpec) {
+	*out = *in
+	if in.DeleteTypes != nil {
+		in, out := &in.DeleteTypes, &out.DeleteTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SnapshotTypes != nil {
+		in, out := &in.SnapshotTypes, &out.SnapshotTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPStreamSpec.
+func (in *HTTPStreamSpec) DeepCopy() *HTTPStreamSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPStreamSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTLSCARef) DeepCopyInto(out *HTTPTLSCARef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTLSCARef.
+func (in *HTTPTLSCARef) DeepCopy() *HTTPTLSCARef {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTLSCARef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTLSClientCertRef) DeepCopyInto(out *HTTPTLSClientCertRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTLSClientCertRef.
+func (in *HTTPTLSClientCertRef) DeepCopy() *HTTPTLSClientCertRef {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTLSClientCertRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTLSSpec) DeepCopyInto(out *HTTPTLSSpec) {
+	*out = *in
+	if in.CARef != nil {
+		in, out := &in.CARef, &out.CARef
+		*out = new(HTTPTLSCARef)
+		**out = **in
+	}
+	if in.ClientCertRef != nil {
+		in, out := &in.ClientCertRef, &out.ClientCertRef
+		*out = new(HTTPTLSClientCertRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTLSSpec.
+func (in *HTTPTLSSpec) DeepCopy() *HTTPTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPTemplateValue) DeepCopyInto(out *HTTPTemplateValue) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(HTTPKeyRef)
+		**out = **in
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(HTTPKeyRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPTemplateValue.
+func (in *HTTPTemplateValue) DeepCopy() *HTTPTemplateValue {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPTemplateValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPWebhookSpec) DeepCopyInto(out *HTTPWebhookSpec) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPWebhookSpec.
+func (in *HTTPWebhookSpec) DeepCopy() *HTTPWebhookSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPWebhookSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"context"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+// Info about the triggering child resource
+type childResourceInfo struct {
+	GVK       schema.GroupVersionKind
+	Namespace string
+	Name      string
+}
+
+// childEventSet records the last child resource event of every HTTPQueryResource until it is reconciled
+type childEventSet struct {
+	mu     sync.Mutex
+	events map[types.NamespacedName]*childResourceInfo
+}
+
+func (s *childEventSet) add(key types.NamespacedName, info *childResourceInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.events == nil {
+		s.events = map[types.NamespacedName]*childResourceInfo{}
+	}
+	s.events[key] = info
+}
+
+func (s *childEventSet) take(key types.NamespacedName) (*childResourceInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info, ok := s.events[key]
+	delete(s.events, key)
+	return info, ok
+}
+
+// childToHTTPQueryResource maps a managed resource to the HTTPQueryResource controlling it, and
+// records the event so that the reconciliation applies the resources in full
+func (r *HTTPQueryResourceReconciler) childToHTTPQueryResource(ctx context.Context, obj client.Object) []reconcile.Request {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.Kind != "HTTPQueryResource" {
+		return nil
+	}
+	if gv, err := schema.ParseGroupVersion(owner.APIVersion); err != nil || gv.Group != httpv1alpha1.GroupVersion.Group {
+		return nil
+	}
+	key := types.NamespacedName{Namespace: obj.GetNamespace(), Name: owner.Name}
+	r.childEvents.add(key, &childResourceInfo{
+		GVK:       obj.GetObjectKind().GroupVersionKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	})
+	return []reconcile.Request{{NamespacedName: key}}
+}
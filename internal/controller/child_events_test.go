@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// conditionalHTTPClient answers conditional queries as unchanged
+type conditionalHTTPClient struct {
+	util.HTTPClient
+	items []util.ItemResult
+}
+
+func (c *conditionalHTTPClient) Query(ctx context.Context, config util.HTTPConfig) (*util.QueryResult, error) {
+	if config.Validators != nil {
+		return &util.QueryResult{Unchanged: true, Validators: *config.Validators}, nil
+	}
+	return &util.QueryResult{Items: c.items, Validators: util.ResponseValidators{BodyHash: "hash"}}, nil
+}
+
+func TestChildToHTTPQueryResource(t *testing.T) {
+	enabled := true
+	child := func(ownerRef metav1.OwnerReference) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name: "user-alice", Namespace: "team-a", OwnerReferences: []metav1.OwnerReference{ownerRef},
+		}}
+		configMap.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		return configMap
+	}
+	key := types.NamespacedName{Namespace: "team-a", Name: "users"}
+
+	r := &HTTPQueryResourceReconciler{}
+	requests := r.childToHTTPQueryResource(context.Background(), child(metav1.OwnerReference{
+		APIVersion: httpv1alpha1.GroupVersion.String(), Kind: "HTTPQueryResource", Name: "users", Controller: &enabled,
+	}))
+	assert.Equal(t, []reconcile.Request{{NamespacedName: key}}, requests)
+
+	info, ok := r.childEvents.take(key)
+	require.True(t, ok)
+	assert.Equal(t, &childResourceInfo{GVK: corev1.SchemeGroupVersion.WithKind("ConfigMap"), Namespace: "team-a", Name: "user-alice"}, info)
+	_, ok = r.childEvents.take(key)
+	assert.False(t, ok, "events are taken once")
+
+	assert.Empty(t, r.childToHTTPQueryResource(context.Background(), child(metav1.OwnerReference{
+		APIVersion: "apps/v1", Kind: "Deployment", Name: "users", Controller: &enabled,
+	})))
+	assert.Empty(t, r.childToHTTPQueryResource(context.Background(), child(metav1.OwnerReference{
+		APIVersion: httpv1alpha1.GroupVersion.String(), Kind: "HTTPQueryResource", Name: "users",
+	})), "not the controller")
+}
+
+func TestReconcile_SkipUnchanged(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, httpv1alpha1.AddToScheme(scheme))
+
+	key := types.NamespacedName{Namespace: "team-a", Name: "users"}
+	reconcileOnce := func(t *testing.T, skipUnchanged, childEvent bool) client.Client {
+		httpQueryResource := &httpv1alpha1.HTTPQueryResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "team-a", Generation: 1, Finalizers: []string{HTTPQueryFinalizer}},
+			Spec: httpv1alpha1.HTTPQueryResourceSpec{
+				PollInterval: "5m",
+				HTTP:         httpv1alpha1.HTTPSpec{URL: "https://api.example.com/users"},
+				Template: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: user-{{ .Item.name }}`,
+				SkipUnchanged: skipUnchanged,
+			},
+			Status: httpv1alpha1.HTTPQueryResourceStatus{
+				ObservedGeneration: 1,
+				LastResponse:       &httpv1alpha1.HTTPResponseValidators{BodyHash: "hash"},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(httpQueryResource).
+			WithStatusSubresource(httpQueryResource).
+			Build()
+		r := &HTTPQueryResourceReconciler{
+			Client: fakeClient,
+			Scheme: scheme,
+			Log:    logr.Discard(),
+			HTTPClientFactory: func(ctx context.Context) (util.HTTPClient, error) {
+				return &conditionalHTTPClient{items: []util.ItemResult{{"name": "alice"}}}, nil
+			},
+			TemplateProcessor: util.NewTemplateProcessor(),
+			OwnedGVKs:         []schema.GroupVersionKind{corev1.SchemeGroupVersion.WithKind("ConfigMap")},
+		}
+		if childEvent {
+			r.childEvents.add(key, &childResourceInfo{GVK: corev1.SchemeGroupVersion.WithKind("ConfigMap"), Namespace: "team-a", Name: "user-alice"})
+		}
+		_, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		return fakeClient
+	}
+	applied := func(t *testing.T, c client.Client) bool {
+		err := c.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "user-alice"}, &corev1.ConfigMap{})
+		require.NoError(t, client.IgnoreNotFound(err))
+		return err == nil
+	}
+
+	assert.True(t, applied(t, reconcileOnce(t, false, false)), "unchanged responses are only skipped with skipUnchanged")
+	assert.False(t, applied(t, reconcileOnce(t, true, false)), "unchanged response is skipped")
+	assert.True(t, applied(t, reconcileOnce(t, true, true)), "child resource events are reconciled in full")
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+const (
+	HTTPEndpointKind        = "HTTPEndpoint"
+	ClusterHTTPEndpointKind = "ClusterHTTPEndpoint"
+
+	// endpointRefIndexKey indexes HTTPQueryResources by the endpoints they reference
+	endpointRefIndexKey = ".spec.endpointRefs"
+)
+
+// endpointRefKey identifies an endpoint in endpointRefIndexKey, e.g. "HTTPEndpoint/team-a/api"
+// or "ClusterHTTPEndpoint/api"
+func endpointRefKey(kind, namespace, name string) string {
+	if kind == ClusterHTTPEndpointKind {
+		return kind + "/" + name
+	}
+	return kind + "/" + namespace + "/" + name
+}
+
+// endpointKind returns the kind of an endpoint reference, defaulting to HTTPEndpoint
+func endpointKind(ref *httpv1alpha1.HTTPEndpointRef) string {
+	if ref.Kind == "" {
+		return HTTPEndpointKind
+	}
+	return ref.Kind
+}
+
+// indexEndpointRefs is the index function of endpointRefIndexKey
+func indexEndpointRefs(obj client.Object) []string {
+	httpQueryResource, ok := obj.(*httpv1alpha1.HTTPQueryResource)
+	if !ok {
+		return nil
+	}
+	seen := map[string]bool{}
+	var refs []string
+	add := func(spec *httpv1alpha1.HTTPSpec) {
+		if spec.EndpointRef == nil {
+			return
+		}
+		ref := endpointRefKey(endpointKind(spec.EndpointRef), httpQueryResource.Namespace, spec.EndpointRef.Name)
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	add(&httpQueryResource.Spec.HTTP)
+	for i := range httpQueryResource.Spec.Sources {
+		add(&httpQueryResource.Spec.Sources[i].HTTPSpec)
+	}
+	return refs
+}
+
+// endpointToHTTPQueryResources maps a changed endpoint to the HTTPQueryResources referencing it
+func (r *HTTPQueryResourceReconciler) endpointToHTTPQueryResources(ctx context.Context, obj client.Object) []reconcile.Request {
+	kind := HTTPEndpointKind
+	if _, ok := obj.(*httpv1alpha1.ClusterHTTPEndpoint); ok {
+		kind = ClusterHTTPEndpointKind
+	}
+	ref := endpointRefKey(kind, obj.GetNamespace(), obj.GetName())
+
+	var list httpv1alpha1.HTTPQueryResourceList
+	if err := r.List(ctx, &list, client.MatchingFields{endpointRefIndexKey: ref}); err != nil {
+		r.Log.Error(err, "Failed to list HTTPQueryResources referencing endpoint", "endpoint", ref)
+		return nil
+	}
+	return uniqueRequests(list.Items)
+}
+
+// getEndpoint returns the spec of the referenced endpoint. HTTPEndpoints are read from the
+// namespace of the HTTPQueryResource.
+func (r *HTTPQueryResourceReconciler) getEndpoint(ctx context.Context, namespace string, ref *httpv1alpha1.HTTPEndpointRef) (*httpv1alpha1.HTTPEndpointSpec, error) {
+	kind := endpointKind(ref)
+	var spec *httpv1alpha1.HTTPEndpointSpec
+	var err error
+	switch kind {
+	case HTTPEndpointKind:
+		endpoint := &httpv1alpha1.HTTPEndpoint{}
+		err = r.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, endpoint)
+		spec = &endpoint.Spec
+	case ClusterHTTPEndpointKind:
+		endpoint := &httpv1alpha1.ClusterHTTPEndpoint{}
+		err = r.Get(ctx, types.NamespacedName{Name: ref.Name}, endpoint)
+		spec = &endpoint.Spec
+	default:
+		return nil, fmt.Errorf("unsupported endpoint kind: %s", kind)
+	}
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%s '%s' not found", kind, ref.Name)
+		}
+		return nil, fmt.Errorf("failed to get %s '%s': %w", kind, ref.Name, err)
+	}
+	if kind == ClusterHTTPEndpointKind {
+		if err := validateClusterEndpoint(spec); err != nil {
+			return nil, fmt.Errorf("%s '%s': %w", kind, ref.Name, err)
+		}
+	}
+	return spec, nil
+}
+
+// applyEndpoint returns the request with the defaults of its endpoint applied, or the request
+// itself if it does not reference an endpoint. The path is appended to the base URL, headers are
+// merged, and the authentication, TLS and timeout of the endpoint are used unless the request sets them.
+func (r *HTTPQueryResourceReconciler) applyEndpoint(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, spec *httpv1alpha1.HTTPSpec) (*httpv1alpha1.HTTPSpec, error) {
+	if spec.EndpointRef == nil {
+		return spec, nil
+	}
+	endpoint, err := r.getEndpoint(ctx, httpQueryResource.Namespace, spec.EndpointRef)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := spec.DeepCopy()
+	merged.URL = joinURL(endpoint.BaseURL, spec.Path)
+	if len(endpoint.Headers) > 0 {
+		merged.Headers = make(map[string]string, len(endpoint.Headers)+len(spec.Headers))
+		for key, value := range endpoint.Headers {
+			merged.Headers[key] = value
+		}
+		for key, value := range spec.Headers {
+			merged.Headers[key] = value
+		}
+	}
+	if merged.AuthenticationRef == nil {
+		merged.AuthenticationRef = endpoint.AuthenticationRef.DeepCopy()
+	}
+	if merged.TLS == nil {
+		merged.TLS = endpoint.TLS.DeepCopy()
+	}
+	if merged.Timeout == "" {
+		merged.Timeout = endpoint.Timeout
+	}
+	return merged, nil
+}
+
+// validateClusterEndpoint checks that the Secrets and ConfigMaps of a ClusterHTTPEndpoint set their
+// namespace, as a cluster-scoped endpoint has no namespace to default to. ServiceAccount tokens are
+// minted in the namespace of the HTTPQueryResource.
+func validateClusterEndpoint(spec *httpv1alpha1.HTTPEndpointSpec) error {
+	if authRef := spec.AuthenticationRef; authRef != nil && authRef.Type != "serviceAccountToken" && authRef.Namespace == "" {
+		return fmt.Errorf("authenticationRef requires a namespace")
+	}
+	if tls := spec.TLS; tls != nil {
+		if tls.CARef != nil && tls.CARef.Namespace == "" {
+			return fmt.Errorf("tls.caRef requires a namespace")
+		}
+		if tls.ClientCertRef != nil && tls.ClientCertRef.Namespace == "" {
+			return fmt.Errorf("tls.clientCertRef requires a namespace")
+		}
+	}
+	return nil
+}
+
+// joinURL appends a path to a base URL with a single slash between them
+func joinURL(baseURL, path string) string {
+	if path == "" {
+		return baseURL
+	}
+	if strings.HasPrefix(path, "?") {
+		return baseURL + path
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
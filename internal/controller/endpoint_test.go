@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+func TestApplyEndpoint(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, httpv1alpha1.AddToScheme(scheme))
+	endpointSpec := httpv1alpha1.HTTPEndpointSpec{
+		BaseURL:           "https://api.example.com/v1/",
+		Headers:           map[string]string{"Accept": "application/json", "X-Tenant": "default"},
+		AuthenticationRef: &httpv1alpha1.HTTPAuthenticationRef{Name: "api-credentials", Type: "bearer"},
+		Timeout:           "10s",
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&httpv1alpha1.HTTPEndpoint{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"}, Spec: endpointSpec},
+			&httpv1alpha1.ClusterHTTPEndpoint{ObjectMeta: metav1.ObjectMeta{Name: "shared-api"}, Spec: endpointSpec},
+		).
+		Build()
+	r := &HTTPQueryResourceReconciler{Client: fakeClient, Log: logr.Discard()}
+	httpQueryResource := &httpv1alpha1.HTTPQueryResource{ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "team-a"}}
+
+	spec, err := r.applyEndpoint(context.Background(), httpQueryResource, &httpv1alpha1.HTTPSpec{
+		EndpointRef: &httpv1alpha1.HTTPEndpointRef{Name: "api"},
+		Path:        "/users?team={{ .Values.team }}",
+		Headers:     map[string]string{"X-Tenant": "acme"},
+		Timeout:     "30s",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/v1/users?team={{ .Values.team }}", spec.URL)
+	assert.Equal(t, map[string]string{"Accept": "application/json", "X-Tenant": "acme"}, spec.Headers)
+	assert.Equal(t, "api-credentials", spec.AuthenticationRef.Name)
+	assert.Equal(t, "30s", spec.Timeout)
+
+	t.Run("request without endpoint", func(t *testing.T) {
+		request := &httpv1alpha1.HTTPSpec{URL: "https://api.example.com/users"}
+		spec, err := r.applyEndpoint(context.Background(), httpQueryResource, request)
+		require.NoError(t, err)
+		assert.Same(t, request, spec)
+	})
+
+	t.Run("missing endpoint", func(t *testing.T) {
+		_, err := r.applyEndpoint(context.Background(), httpQueryResource, &httpv1alpha1.HTTPSpec{
+			EndpointRef: &httpv1alpha1.HTTPEndpointRef{Name: "missing"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HTTPEndpoint 'missing' not found")
+	})
+
+	t.Run("cluster endpoint secrets require a namespace", func(t *testing.T) {
+		_, err := r.applyEndpoint(context.Background(), httpQueryResource, &httpv1alpha1.HTTPSpec{
+			EndpointRef: &httpv1alpha1.HTTPEndpointRef{Kind: ClusterHTTPEndpointKind, Name: "shared-api"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "authenticationRef requires a namespace")
+	})
+}
+
+func TestJoinURL(t *testing.T) {
+	tests := []struct {
+		baseURL, path, expected string
+	}{
+		{"https://api.example.com/v1", "", "https://api.example.com/v1"},
+		{"https://api.example.com/v1", "users", "https://api.example.com/v1/users"},
+		{"https://api.example.com/v1/", "/users", "https://api.example.com/v1/users"},
+		{"https://api.example.com/v1", "?page=2", "https://api.example.com/v1?page=2"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, joinURL(tt.baseURL, tt.path), "%s + %s", tt.baseURL, tt.path)
+	}
+}
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+const (
+	ConditionReachable     = "Reachable"
+	ConditionAuthenticated = "Authenticated"
+
+	// DefaultEndpointHealthCheckInterval is how often endpoints are checked by default
+	DefaultEndpointHealthCheckInterval = 5 * time.Minute
+)
+
+// HTTPEndpointReconciler checks the health of HTTPEndpoints, or of ClusterHTTPEndpoints if ClusterScoped is set
+type HTTPEndpointReconciler struct {
+	client.Client
+	Log          logr.Logger
+	Prober       util.HTTPProber
+	AuthResolver *util.AuthResolver
+	// ClusterScoped selects ClusterHTTPEndpoints instead of HTTPEndpoints
+	ClusterScoped bool
+}
+
+// newEndpoint returns an empty endpoint of the reconciled kind with its spec and status
+func (r *HTTPEndpointReconciler) newEndpoint() (client.Object, *httpv1alpha1.HTTPEndpointSpec, *httpv1alpha1.HTTPEndpointStatus) {
+	if r.ClusterScoped {
+		endpoint := &httpv1alpha1.ClusterHTTPEndpoint{}
+		return endpoint, &endpoint.Spec, &endpoint.Status
+	}
+	endpoint := &httpv1alpha1.HTTPEndpoint{}
+	return endpoint, &endpoint.Spec, &endpoint.Status
+}
+
+// Reconcile checks the health of an endpoint and records it in the Reachable and Authenticated conditions
+func (r *HTTPEndpointReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("endpoint", req.NamespacedName)
+
+	endpoint, spec, status := r.newEndpoint()
+	if err := r.Get(ctx, req.NamespacedName, endpoint); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	interval := DefaultEndpointHealthCheckInterval
+	if spec.HealthCheck != nil && spec.HealthCheck.Interval != "" {
+		parsed, err := time.ParseDuration(spec.HealthCheck.Interval)
+		if err != nil {
+			log.Error(err, "Invalid health check interval", "interval", spec.HealthCheck.Interval)
+		} else {
+			interval = parsed
+		}
+	}
+
+	reachable, authenticated := r.check(ctx, endpoint.GetNamespace(), spec)
+	reachable.ObservedGeneration = endpoint.GetGeneration()
+	authenticated.ObservedGeneration = endpoint.GetGeneration()
+	meta.SetStatusCondition(&status.Conditions, reachable)
+	meta.SetStatusCondition(&status.Conditions, authenticated)
+	now := metav1.Now()
+	status.LastCheckTime = &now
+	status.ObservedGeneration = endpoint.GetGeneration()
+
+	if err := r.Status().Update(ctx, endpoint); err != nil {
+		log.Error(err, "Failed to update endpoint status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// check probes the endpoint and returns its Reachable and Authenticated conditions
+func (r *HTTPEndpointReconciler) check(ctx context.Context, namespace string, spec *httpv1alpha1.HTTPEndpointSpec) (reachable, authenticated metav1.Condition) {
+	reachable = metav1.Condition{Type: ConditionReachable, Status: metav1.ConditionUnknown, Reason: "NotChecked"}
+	authenticated = metav1.Condition{Type: ConditionAuthenticated, Status: metav1.ConditionTrue, Reason: "NoAuthentication", Message: "The endpoint does not configure authentication"}
+
+	if r.AuthResolver == nil {
+		r.AuthResolver = util.NewAuthResolver(r.Client, r.Log)
+	}
+
+	path := ""
+	if spec.HealthCheck != nil {
+		path = spec.HealthCheck.Path
+	}
+	timeout, err := parseDuration("timeout", spec.Timeout)
+	if err != nil {
+		reachable.Message = err.Error()
+		return reachable, authenticated
+	}
+	config := util.HTTPConfig{
+		URL:     joinURL(spec.BaseURL, path),
+		Method:  "GET",
+		Headers: spec.Headers,
+		Timeout: timeout,
+	}
+
+	if r.ClusterScoped {
+		if err := validateClusterEndpoint(spec); err != nil {
+			reachable.Message = err.Error()
+			authenticated = metav1.Condition{Type: ConditionAuthenticated, Status: metav1.ConditionFalse, Reason: "InvalidSpec", Message: err.Error()}
+			return reachable, authenticated
+		}
+	}
+
+	if authRef := spec.AuthenticationRef; authRef != nil {
+		if r.ClusterScoped && authRef.Type == "serviceAccountToken" {
+			// Tokens are minted in the namespace of every HTTPQueryResource, so there is none to check
+			authenticated = metav1.Condition{Type: ConditionAuthenticated, Status: metav1.ConditionUnknown, Reason: "NotChecked",
+				Message: "ServiceAccount tokens of a ClusterHTTPEndpoint are minted in the namespace of each HTTPQueryResource"}
+		} else {
+			authConfig, err := r.AuthResolver.ResolveAuthenticationConfig(ctx, namespace, authRef)
+			if err != nil {
+				reachable.Message = "Credentials could not be resolved"
+				authenticated = metav1.Condition{Type: ConditionAuthenticated, Status: metav1.ConditionFalse, Reason: "CredentialsError", Message: err.Error()}
+				return reachable, authenticated
+			}
+			config.AuthType = authConfig.AuthType
+			config.AuthConfig = authConfig.AuthConfig
+			config.OnRefreshToken = r.AuthResolver.RefreshTokenWriter(authConfig)
+		}
+	}
+
+	if spec.TLS != nil {
+		tlsConfig, err := r.AuthResolver.ResolveTLSConfig(ctx, namespace, spec.TLS)
+		if err != nil {
+			reachable = metav1.Condition{Type: ConditionReachable, Status: metav1.ConditionFalse, Reason: "TLSError", Message: err.Error()}
+			return reachable, authenticated
+		}
+		config.TLS = tlsConfig
+	}
+
+	statusCode, err := r.Prober.Probe(ctx, config)
+	if statusCode > 0 {
+		reachable = metav1.Condition{Type: ConditionReachable, Status: metav1.ConditionTrue, Reason: "Reachable", Message: fmt.Sprintf("HTTP status %d", statusCode)}
+	} else if err != nil && !errors.Is(err, util.ErrAuthentication) {
+		reachable = metav1.Condition{Type: ConditionReachable, Status: metav1.ConditionFalse, Reason: "Unreachable", Message: err.Error()}
+		if spec.AuthenticationRef != nil {
+			authenticated = metav1.Condition{Type: ConditionAuthenticated, Status: metav1.ConditionUnknown, Reason: "NotChecked", Message: "The endpoint is unreachable"}
+		}
+		return reachable, authenticated
+	}
+
+	if errors.Is(err, util.ErrAuthentication) {
+		if statusCode == 0 {
+			reachable.Message = "Credentials could not be applied"
+		}
+		authenticated = metav1.Condition{Type: ConditionAuthenticated, Status: metav1.ConditionFalse, Reason: "AuthenticationFailed", Message: err.Error()}
+	} else if spec.AuthenticationRef != nil && authenticated.Status != metav1.ConditionUnknown {
+		authenticated = metav1.Condition{Type: ConditionAuthenticated, Status: metav1.ConditionTrue, Reason: "Authenticated", Message: "The endpoint accepted the credentials"}
+	}
+	return reachable, authenticated
+}
+
+// indexEndpointSecretRefs is the secretRefIndexKey index function of HTTPEndpoints and ClusterHTTPEndpoints
+func indexEndpointSecretRefs(obj client.Object) []string {
+	switch endpoint := obj.(type) {
+	case *httpv1alpha1.HTTPEndpoint:
+		return endpointSecrets(endpoint.Namespace, &endpoint.Spec)
+	case *httpv1alpha1.ClusterHTTPEndpoint:
+		return endpointSecrets("", &endpoint.Spec)
+	}
+	return nil
+}
+
+// secretToEndpoints maps a changed Secret to the endpoints referencing it
+func (r *HTTPEndpointReconciler) secretToEndpoints(ctx context.Context, obj client.Object) []reconcile.Request {
+	secretRef := client.ObjectKeyFromObject(obj).String()
+	var requests []reconcile.Request
+	if r.ClusterScoped {
+		var list httpv1alpha1.ClusterHTTPEndpointList
+		if err := r.List(ctx, &list, client.MatchingFields{secretRefIndexKey: secretRef}); err != nil {
+			r.Log.Error(err, "Failed to list ClusterHTTPEndpoints referencing secret", "secret", secretRef)
+			return nil
+		}
+		for _, item := range list.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+		}
+		return requests
+	}
+	var list httpv1alpha1.HTTPEndpointList
+	if err := r.List(ctx, &list, client.MatchingFields{secretRefIndexKey: secretRef}); err != nil {
+		r.Log.Error(err, "Failed to list HTTPEndpoints referencing secret", "secret", secretRef)
+		return nil
+	}
+	for _, item := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&item)})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HTTPEndpointReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	endpoint, _, _ := r.newEndpoint()
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), endpoint, secretRefIndexKey, indexEndpointSecretRefs); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		// Status updates of the health check must not trigger another check
+		For(endpoint, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretToEndpoints),
+			builder.WithPredicates(secretDataChangedPredicate())).
+		Complete(r)
+}
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+func TestHTTPEndpointReconciler_Reconcile(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, httpv1alpha1.AddToScheme(scheme))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/health", r.URL.Path)
+		if r.Header.Get("Authorization") != "Bearer valid-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	check := func(t *testing.T, token string) (reachable, authenticated *metav1.Condition) {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "team-a"},
+			Data:       map[string][]byte{"token": []byte(token)},
+		}
+		endpoint := &httpv1alpha1.HTTPEndpoint{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"},
+			Spec: httpv1alpha1.HTTPEndpointSpec{
+				BaseURL:           server.URL + "/v1",
+				AuthenticationRef: &httpv1alpha1.HTTPAuthenticationRef{Name: "api-token", Type: "bearer"},
+				HealthCheck:       &httpv1alpha1.HTTPEndpointHealthCheckSpec{Path: "/health", Interval: "1m"},
+			},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(secret, endpoint).
+			WithStatusSubresource(endpoint).
+			Build()
+		r := &HTTPEndpointReconciler{Client: fakeClient, Log: logr.Discard(), Prober: util.NewRESTClient()}
+
+		key := types.NamespacedName{Namespace: "team-a", Name: "api"}
+		result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+		require.NoError(t, err)
+		assert.Equal(t, "1m0s", result.RequeueAfter.String())
+
+		updated := &httpv1alpha1.HTTPEndpoint{}
+		require.NoError(t, fakeClient.Get(context.Background(), key, updated))
+		require.NotNil(t, updated.Status.LastCheckTime)
+		return meta.FindStatusCondition(updated.Status.Conditions, ConditionReachable),
+			meta.FindStatusCondition(updated.Status.Conditions, ConditionAuthenticated)
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		reachable, authenticated := check(t, "valid-token")
+		assert.Equal(t, metav1.ConditionTrue, reachable.Status)
+		assert.Equal(t, "HTTP status 204", reachable.Message)
+		assert.Equal(t, metav1.ConditionTrue, authenticated.Status)
+		assert.Equal(t, "Authenticated", authenticated.Reason)
+	})
+
+	t.Run("rejected credentials", func(t *testing.T) {
+		reachable, authenticated := check(t, "expired-token")
+		assert.Equal(t, metav1.ConditionTrue, reachable.Status)
+		assert.Equal(t, metav1.ConditionFalse, authenticated.Status)
+		assert.Equal(t, "AuthenticationFailed", authenticated.Reason)
+	})
+}
@@ -3,24 +3,31 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
 	"github.com/konnektr-io/http-query-operator/internal/util"
@@ -31,7 +38,11 @@ const (
 	ControllerName         = "httpqueryresource-controller"
 	ConditionReconciled    = "Reconciled"
 	ConditionHTTPConnected = "HTTPConnected"
+	ConditionDegraded      = "Degraded"
 	HTTPQueryFinalizer     = "konnektr.io/httpqueryresource-finalizer"
+
+	TemplateModePerItem   = "perItem"
+	TemplateModeAggregate = "aggregate"
 )
 
 // HTTPQueryResourceReconciler reconciles an HTTPQueryResource object
@@ -43,22 +54,26 @@ type HTTPQueryResourceReconciler struct {
 	OwnedGVKs         []schema.GroupVersionKind
 	AuthResolver      *util.AuthResolver
 	TemplateProcessor *util.TemplateProcessor
-}
-
-// Key for context value to indicate child resource event
-var childResourceEventKey = struct{}{}
-
-// Info about the triggering child resource
-type childResourceInfo struct {
-	GVK       schema.GroupVersionKind
-	Namespace string
-	Name      string
+	// Recorder emits Events about the HTTPQueryResource. No Events are emitted if nil.
+	Recorder record.EventRecorder
+	// Webhooks receives the webhook calls that trigger reconciliations. Webhooks are disabled if nil.
+	Webhooks *WebhookReceiver
+
+	// streams holds the streams of resources in stream mode
+	streams *streamManager
+	// childEvents holds the pending child resource events
+	childEvents childEventSet
 }
 
 //+kubebuilder:rbac:groups=konnektr.io,resources=httpqueryresources,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=konnektr.io,resources=httpqueryresources/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=konnektr.io,resources=httpqueryresources/finalizers,verbs=update
-//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=konnektr.io,resources=httpendpoints;clusterhttpendpoints,verbs=get;list;watch
+//+kubebuilder:rbac:groups=konnektr.io,resources=httpendpoints/status;clusterhttpendpoints/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=serviceaccounts/token,verbs=create
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
 //+kubebuilder:rbac:groups="*",resources="*",verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop
@@ -68,7 +83,7 @@ func (r *HTTPQueryResourceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	log.Info("Reconciling HTTPQueryResource", "Request.Namespace", req.Namespace, "Request.Name", req.Name)
 
 	// Check if this reconciliation was triggered by a child resource
-	childInfo, isChildEvent := ctx.Value(childResourceEventKey).(*childResourceInfo)
+	childInfo, isChildEvent := r.childEvents.take(req.NamespacedName)
 	if isChildEvent {
 		log.Info("Reconciliation triggered by child resource event",
 			"child-gvk", childInfo.GVK.String(),
@@ -82,6 +97,7 @@ func (r *HTTPQueryResourceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			log.Info("HTTPQueryResource not found. Ignoring since object must be deleted")
+			r.stopStream(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		log.Error(err, "Failed to get HTTPQueryResource")
@@ -93,6 +109,11 @@ func (r *HTTPQueryResourceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return r.handleDeletion(ctx, httpQueryResource)
 	}
 
+	// A changed managed resource is reverted even if the response is unchanged
+	if isChildEvent {
+		httpQueryResource.Status.LastResponse = nil
+	}
+
 	// Add finalizer if not present
 	if !controllerutil.ContainsFinalizer(httpQueryResource, HTTPQueryFinalizer) {
 		controllerutil.AddFinalizer(httpQueryResource, HTTPQueryFinalizer)
@@ -117,13 +138,25 @@ func (r *HTTPQueryResourceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	// Set HTTP connected condition
 	r.setCondition(httpQueryResource, ConditionHTTPConnected, metav1.ConditionTrue, "HTTPClientConnected", "HTTP client successfully initialized")
 
+	httpQueryResource.Status.WebhookPath = ""
+	if httpQueryResource.Spec.Webhook != nil && r.Webhooks != nil {
+		httpQueryResource.Status.WebhookPath = webhookPath(req.NamespacedName)
+	}
+
 	// Execute the reconciliation
 	result, err := r.reconcileResources(ctx, httpQueryResource, httpClient)
 
 	// Always set ConditionReconciled to True with Reason 'Success' if no error, matching database controller
-	if err != nil {
+	if errors.Is(err, errStreamPending) {
+		// The stream triggers a reconciliation once its items are delivered
+		log.Info("Waiting for the stream to deliver its items")
+		r.setCondition(httpQueryResource, ConditionReconciled, metav1.ConditionFalse, "StreamPending", err.Error())
+		err = nil
+	} else if err != nil {
 		log.Error(err, "Failed to reconcile resources")
 		r.setCondition(httpQueryResource, ConditionReconciled, metav1.ConditionFalse, "ReconciliationError", err.Error())
+		// Force a full reconciliation on the next poll, even if the response is unchanged
+		httpQueryResource.Status.LastResponse = nil
 	} else {
 		r.setCondition(httpQueryResource, ConditionReconciled, metav1.ConditionTrue, "Success", "Successfully reconciled all resources")
 	}
@@ -152,6 +185,7 @@ func (r *HTTPQueryResourceReconciler) Reconcile(ctx context.Context, req ctrl.Re
 func (r *HTTPQueryResourceReconciler) handleDeletion(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource) (ctrl.Result, error) {
 	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
 	log.Info("Handling deletion of HTTPQueryResource")
+	r.stopStream(client.ObjectKeyFromObject(httpQueryResource))
 
 	// Delete all managed resources
 	if err := r.deleteOwnedResources(ctx, httpQueryResource); err != nil {
@@ -203,45 +237,119 @@ func (r *HTTPQueryResourceReconciler) setCondition(httpQueryResource *httpv1alph
 func (r *HTTPQueryResourceReconciler) reconcileResources(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, httpClient util.HTTPClient) (ctrl.Result, error) {
 	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
 
-	// Create HTTP config from HTTPQueryResource
-	httpConfig := util.HTTPConfig{
-		URL:          httpQueryResource.Spec.HTTP.URL,
-		Method:       httpQueryResource.Spec.HTTP.Method,
-		Headers:      httpQueryResource.Spec.HTTP.Headers,
-		Body:         httpQueryResource.Spec.HTTP.Body,
-		ResponsePath: httpQueryResource.Spec.HTTP.ResponsePath,
+	// Record request attempts in the status
+	requestStatus := &httpv1alpha1.HTTPRequestStatus{}
+	httpQueryResource.Status.Requests = requestStatus
+
+	httpConfig, err := r.httpConfig(ctx, httpQueryResource, "http", &httpQueryResource.Spec.HTTP, func(attempt util.RetryAttempt) {
+		requestStatus.PollAttempts++
+		if attempt.Backoff > 0 {
+			requestStatus.LastRetryReason = "poll: " + attempt.Reason()
+		}
+	})
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
-	// Set authentication config if provided
-	if httpQueryResource.Spec.HTTP.AuthenticationRef != nil {
-		// Initialize AuthResolver if not set
-		if r.AuthResolver == nil {
-			r.AuthResolver = util.NewAuthResolver(r.Client, r.Log)
+	var queryResult *util.QueryResult
+	var validators *util.ResponseValidators
+	// Streams only prune once they confirmed that they hold every item
+	complete := true
+	if httpQueryResource.Spec.HTTP.Stream == nil {
+		r.stopStream(client.ObjectKeyFromObject(httpQueryResource))
+	}
+	call := r.takeWebhookCall(httpQueryResource)
+	switch {
+	case httpQueryResource.Spec.HTTP.Stream != nil:
+		// The items folded from the stream replace the poll response
+		items, streamComplete, err := r.streamItems(httpQueryResource, httpClient, httpConfig)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		complete = streamComplete
+		queryResult = &util.QueryResult{Items: items}
+		httpQueryResource.Status.LastResponse = nil
+	case call != nil && call.items != nil:
+		// The payload of the webhook call replaces the poll response. It has no validators,
+		// so the next poll is reconciled in full.
+		log.Info("Using webhook payload instead of executing HTTP request", "items", len(call.items))
+		queryResult = &util.QueryResult{Items: call.items}
+		httpQueryResource.Status.LastResponse = nil
+	default:
+		// Send the validators of the last response only if an unchanged response can be skipped
+		skipUnchanged := canSkipUnchanged(httpQueryResource)
+		if skipUnchanged {
+			httpConfig.Validators = &util.ResponseValidators{
+				ETag:         httpQueryResource.Status.LastResponse.ETag,
+				LastModified: httpQueryResource.Status.LastResponse.LastModified,
+				BodyHash:     httpQueryResource.Status.LastResponse.BodyHash,
+			}
 		}
 
-		authConfig, err := r.AuthResolver.ResolveAuthenticationConfig(ctx, httpQueryResource.Namespace, httpQueryResource.Spec.HTTP.AuthenticationRef)
+		// Execute HTTP request
+		log.Info("Executing HTTP request", "url", httpConfig.URL)
+		queryResult, err = httpClient.Query(ctx, httpConfig)
 		if err != nil {
-			log.Error(err, "Failed to resolve authentication configuration")
+			log.Error(err, "Failed to execute HTTP request")
 			return ctrl.Result{}, err
 		}
-		httpConfig.AuthType = authConfig.AuthType
-		httpConfig.AuthConfig = authConfig.AuthConfig
+
+		if queryResult.Unchanged {
+			if !skipUnchanged {
+				// Declared unchanged by its status code, although the last response cannot be
+				// skipped. Reconcile the next response in full.
+				httpQueryResource.Status.LastResponse = nil
+			}
+			log.Info("HTTP response unchanged, skipping templating and apply")
+			return r.reconcileUnchanged(ctx, httpQueryResource, httpClient)
+		}
+		validators = &queryResult.Validators
 	}
 
-	// Execute HTTP request
-	log.Info("Executing HTTP request", "url", httpConfig.URL)
-	items, err := httpClient.Execute(ctx, httpConfig)
-	if err != nil {
-		log.Error(err, "Failed to execute HTTP request")
+	// Drop the items not selected by the filter
+	if queryResult.Items, err = filterItems("http", httpQueryResource.Spec.HTTP.Filter, queryResult.Items); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	// Process response and apply resources
-	resources, err := r.processHTTPResponse(ctx, httpQueryResource, items)
+	// Request the additional sources and join them to the items
+	input, err := r.fetchSources(ctx, httpQueryResource, httpClient, queryResult.Items)
 	if err != nil {
+		log.Error(err, "Failed to execute source HTTP requests")
+		return ctrl.Result{}, err
+	}
+	input.Response = queryResult.Response
+	input.Header = queryResult.Header
+	input.Namespace = httpQueryResource.Namespace
+	input.Context = ctx
+	input.ItemKey = httpQueryResource.Spec.ItemKey
+
+	// Process response and apply resources. The resources of the other items are applied when
+	// only some items fail.
+	resources, err := r.processHTTPResponse(ctx, httpQueryResource, queryResult.Items, input)
+	var templateErr *util.TemplateError
+	if errors.As(err, &templateErr) {
+		r.recordItemFailures(httpQueryResource, templateErr)
+	}
+	if err != nil && (templateErr == nil || !templateErr.Partial()) {
 		log.Error(err, "Failed to process HTTP response")
 		return ctrl.Result{}, err
 	}
+	if templateErr != nil {
+		log.Info("Some items failed to process", "failedItems", templateErr.Indexes())
+		// Retry the failed items on the next poll, even if the response is unchanged
+		validators = nil
+		httpQueryResource.Status.LastResponse = nil
+	} else {
+		r.recordItemFailures(httpQueryResource, nil)
+	}
+
+	// Keep the names of the existing resources of the items
+	if httpQueryResource.Spec.ItemKey != "" {
+		if err := r.keepResourceNames(ctx, httpQueryResource, resources); err != nil {
+			log.Error(err, "Failed to match resources to existing resources")
+			return ctrl.Result{}, err
+		}
+	}
 
 	// Apply the resources to the cluster
 	for _, resource := range resources {
@@ -252,8 +360,13 @@ func (r *HTTPQueryResourceReconciler) reconcileResources(ctx context.Context, ht
 	}
 
 	// Clean up resources that are no longer in the response
-	if httpQueryResource.Spec.Prune != nil && *httpQueryResource.Spec.Prune {
-		if err := r.cleanupUnmanagedResources(ctx, httpQueryResource, resources); err != nil {
+	keptKeys, keepAll := keptItemKeys(httpQueryResource, templateErr)
+	if keepAll {
+		log.Info("Not pruning resources while items fail to process", "failurePolicy", FailurePolicyKeepExisting)
+	} else if !complete {
+		log.Info("Not pruning resources until the stream sends a snapshot event")
+	} else if httpQueryResource.Spec.Prune != nil && *httpQueryResource.Spec.Prune {
+		if err := r.cleanupUnmanagedResources(ctx, httpQueryResource, resources, keptKeys); err != nil {
 			log.Error(err, "Failed to cleanup unmanaged resources")
 			return ctrl.Result{}, err
 		}
@@ -269,6 +382,7 @@ func (r *HTTPQueryResourceReconciler) reconcileResources(ctx context.Context, ht
 		sort.Strings(managedResourceNames)
 	}
 	httpQueryResource.Status.ManagedResources = managedResourceNames
+	r.recordSuccessfulPoll(httpQueryResource, validators)
 
 	// Execute status update callbacks for managed resources if configured
 	if httpQueryResource.Spec.StatusUpdate != nil {
@@ -282,8 +396,311 @@ func (r *HTTPQueryResourceReconciler) reconcileResources(ctx context.Context, ht
 	return ctrl.Result{}, nil
 }
 
+// httpConfig builds the HTTP client configuration of a request spec, rendering its URL, header
+// and body templates and resolving its authentication and TLS settings.
+// The field is the path of the spec, used in error messages.
+func (r *HTTPQueryResourceReconciler) httpConfig(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, field string, spec *httpv1alpha1.HTTPSpec, onAttempt func(util.RetryAttempt)) (util.HTTPConfig, error) {
+	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
+
+	// Apply the connection settings of the referenced endpoint
+	spec, err := r.applyEndpoint(ctx, httpQueryResource, spec)
+	if err != nil {
+		return util.HTTPConfig{}, fmt.Errorf("%s.endpointRef: %w", field, err)
+	}
+	if spec.Stream != nil && field != "http" {
+		return util.HTTPConfig{}, fmt.Errorf("%s.stream is only supported by http", field)
+	}
+	if spec.Stream != nil && spec.Transform != "" {
+		return util.HTTPConfig{}, fmt.Errorf("%s.transform cannot be combined with stream", field)
+	}
+
+	retry, err := retryConfig(spec.Retry, onAttempt)
+	if err != nil {
+		return util.HTTPConfig{}, err
+	}
+
+	timeout, err := parseDuration(field+".timeout", spec.Timeout)
+	if err != nil {
+		return util.HTTPConfig{}, err
+	}
+
+	statusCodes, err := statusCodeHandling(field, spec.StatusCodeHandling)
+	if err != nil {
+		return util.HTTPConfig{}, err
+	}
+
+	// Render the URL, headers and body templates of the request
+	requestData, err := r.requestTemplateData(ctx, httpQueryResource, spec.Values)
+	if err != nil {
+		return util.HTTPConfig{}, err
+	}
+	request, err := r.renderRequest(spec.URL, spec.Headers, spec.Body, requestData)
+	if err != nil {
+		return util.HTTPConfig{}, err
+	}
+
+	httpConfig := util.HTTPConfig{
+		URL:                request.URL,
+		Method:             spec.Method,
+		Headers:            request.Headers,
+		Body:               request.Body,
+		ResponsePath:       spec.ResponsePath,
+		ResponseFormat:     spec.ResponseFormat,
+		Transform:          spec.Transform,
+		Pagination:         paginationConfig(spec.Pagination),
+		ItemDetail:         itemDetailConfig(spec.ItemDetail),
+		Retry:              retry,
+		Timeout:            timeout,
+		MaxResponseBytes:   spec.MaxResponseBytes,
+		StatusCodeHandling: statusCodes,
+	}
+
+	if spec.GraphQL != nil {
+		if httpConfig.GraphQL, err = r.renderGraphQL(spec.GraphQL, requestData); err != nil {
+			return util.HTTPConfig{}, err
+		}
+	}
+
+	// Set authentication config if provided
+	if spec.AuthenticationRef != nil {
+		// Initialize AuthResolver if not set
+		if r.AuthResolver == nil {
+			r.AuthResolver = util.NewAuthResolver(r.Client, r.Log)
+		}
+
+		authConfig, err := r.AuthResolver.ResolveAuthenticationConfig(ctx, httpQueryResource.Namespace, spec.AuthenticationRef)
+		if err != nil {
+			log.Error(err, "Failed to resolve authentication configuration")
+			return util.HTTPConfig{}, err
+		}
+		httpConfig.AuthType = authConfig.AuthType
+		httpConfig.AuthConfig = authConfig.AuthConfig
+		httpConfig.OnRefreshToken = r.AuthResolver.RefreshTokenWriter(authConfig)
+	}
+
+	// Set TLS config if provided
+	if spec.TLS != nil {
+		if r.AuthResolver == nil {
+			r.AuthResolver = util.NewAuthResolver(r.Client, r.Log)
+		}
+
+		tlsConfig, err := r.AuthResolver.ResolveTLSConfig(ctx, httpQueryResource.Namespace, spec.TLS)
+		if err != nil {
+			log.Error(err, "Failed to resolve TLS configuration")
+			return util.HTTPConfig{}, err
+		}
+		httpConfig.TLS = tlsConfig
+	}
+
+	return httpConfig, nil
+}
+
+// fetchSources requests every additional source once and joins its items to the primary items
+func (r *HTTPQueryResourceReconciler) fetchSources(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, httpClient util.HTTPClient, items []util.ItemResult) (util.TemplateInput, error) {
+	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
+
+	input := util.TemplateInput{
+		Sources: make(map[string][]util.ItemResult, len(httpQueryResource.Spec.Sources)),
+		Joined:  make([]map[string][]util.ItemResult, len(items)),
+	}
+	for i := range input.Joined {
+		input.Joined[i] = map[string][]util.ItemResult{}
+	}
+
+	for i := range httpQueryResource.Spec.Sources {
+		source := &httpQueryResource.Spec.Sources[i]
+		httpConfig, err := r.httpConfig(ctx, httpQueryResource, "sources."+source.Name, &source.HTTPSpec, func(attempt util.RetryAttempt) {
+			if attempt.Backoff > 0 && httpQueryResource.Status.Requests != nil {
+				httpQueryResource.Status.Requests.LastRetryReason = "source " + source.Name + ": " + attempt.Reason()
+			}
+		})
+		if err != nil {
+			return util.TemplateInput{}, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+
+		log.Info("Executing source HTTP request", "source", source.Name, "url", httpConfig.URL)
+		sourceItems, err := httpClient.Execute(ctx, httpConfig)
+		if err != nil {
+			return util.TemplateInput{}, fmt.Errorf("source %s: %w", source.Name, err)
+		}
+		if sourceItems, err = filterItems("sources."+source.Name, source.Filter, sourceItems); err != nil {
+			return util.TemplateInput{}, err
+		}
+		input.Sources[source.Name] = sourceItems
+
+		if source.Join == nil {
+			continue
+		}
+		joined, err := util.JoinItems(items, sourceItems, util.JoinConfig{
+			ItemKey:   source.Join.ItemKey,
+			SourceKey: source.Join.SourceKey,
+		})
+		if err != nil {
+			return util.TemplateInput{}, fmt.Errorf("failed to join source %s: %w", source.Name, err)
+		}
+		for i := range joined {
+			input.Joined[i][source.Name] = joined[i]
+		}
+	}
+
+	return input, nil
+}
+
+// filterItems returns the items selected by the CEL filter expression of a request spec.
+// The field is the path of the spec, used in error messages.
+func filterItems(field, expression string, items []util.ItemResult) ([]util.ItemResult, error) {
+	if expression == "" {
+		return items, nil
+	}
+	filter, err := util.CompileItemFilter(expression)
+	if err != nil {
+		return nil, fmt.Errorf("%s.filter: %w", field, err)
+	}
+	filtered, err := filter.Filter(items)
+	if err != nil {
+		return nil, fmt.Errorf("%s.filter: %w", field, err)
+	}
+	return filtered, nil
+}
+
+// canSkipUnchanged reports whether an unchanged response may skip templating and apply.
+// This requires skipUnchanged and the current spec to have been reconciled successfully from the last response.
+// Item details and additional sources may change while the list is unchanged, so they are always fetched.
+func canSkipUnchanged(httpQueryResource *httpv1alpha1.HTTPQueryResource) bool {
+	return httpQueryResource.Spec.SkipUnchanged &&
+		httpQueryResource.Spec.HTTP.ItemDetail == nil &&
+		len(httpQueryResource.Spec.Sources) == 0 &&
+		httpQueryResource.Status.LastResponse != nil &&
+		httpQueryResource.Status.ObservedGeneration == httpQueryResource.Generation
+}
+
+// reconcileUnchanged handles a poll whose response has not changed since the last reconciliation.
+// The managed resources are left as they are, but status update callbacks are still sent.
+func (r *HTTPQueryResourceReconciler) reconcileUnchanged(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, httpClient util.HTTPClient) (ctrl.Result, error) {
+	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
+
+	r.recordSuccessfulPoll(httpQueryResource, nil)
+
+	if httpQueryResource.Spec.StatusUpdate != nil {
+		resources, err := r.listOwnedResources(ctx, httpQueryResource)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.updateStatusForChildResources(ctx, httpQueryResource, resources, httpClient); err != nil {
+			log.Error(err, "Failed to execute status updates for child resources")
+			// Don't fail reconciliation for status update errors
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// recordSuccessfulPoll records the poll time, the processed generation and, if given, the
+// validators of the response in the status.
+func (r *HTTPQueryResourceReconciler) recordSuccessfulPoll(httpQueryResource *httpv1alpha1.HTTPQueryResource, validators *util.ResponseValidators) {
+	now := metav1.Now()
+	httpQueryResource.Status.LastPollTime = &now
+	httpQueryResource.Status.ObservedGeneration = httpQueryResource.Generation
+	if validators != nil {
+		httpQueryResource.Status.LastResponse = &httpv1alpha1.HTTPResponseValidators{
+			ETag:         validators.ETag,
+			LastModified: validators.LastModified,
+			BodyHash:     validators.BodyHash,
+		}
+	}
+}
+
+// statusCodeHandling converts the status code handling of a request spec into the HTTP client configuration
+func statusCodeHandling(field string, spec map[string]httpv1alpha1.HTTPStatusCodeAction) (map[int]string, error) {
+	if len(spec) == 0 {
+		return nil, nil
+	}
+	handling := make(map[int]string, len(spec))
+	for code, action := range spec {
+		statusCode, err := strconv.Atoi(code)
+		if err != nil || statusCode < 100 || statusCode > 599 {
+			return nil, fmt.Errorf("invalid %s.statusCodeHandling status code '%s'", field, code)
+		}
+		// Only the poll response is kept, so that sources cannot fall back to their previous items
+		if string(action) == util.StatusCodeUnchanged && field != "http" {
+			return nil, fmt.Errorf("%s.statusCodeHandling '%s': unchanged is only supported by http", field, code)
+		}
+		handling[statusCode] = string(action)
+	}
+	return handling, nil
+}
+
+// paginationConfig converts the pagination spec into the HTTP client configuration
+func paginationConfig(spec *httpv1alpha1.HTTPPaginationSpec) *util.PaginationConfig {
+	if spec == nil {
+		return nil
+	}
+	return &util.PaginationConfig{
+		Type:        spec.Type,
+		CursorPath:  spec.CursorPath,
+		CursorParam: spec.CursorParam,
+		OffsetParam: spec.OffsetParam,
+		PageParam:   spec.PageParam,
+		LimitParam:  spec.LimitParam,
+		Limit:       spec.Limit,
+		StartPage:   spec.StartPage,
+		MaxPages:    spec.MaxPages,
+	}
+}
+
+// itemDetailConfig converts the item detail spec into the HTTP client configuration
+func itemDetailConfig(spec *httpv1alpha1.HTTPItemDetailSpec) *util.ItemDetailConfig {
+	if spec == nil {
+		return nil
+	}
+	return &util.ItemDetailConfig{
+		URL:          spec.URL,
+		Method:       spec.Method,
+		Headers:      spec.Headers,
+		Body:         spec.Body,
+		ResponsePath: spec.ResponsePath,
+		Merge:        spec.Merge,
+		Concurrency:  spec.Concurrency,
+	}
+}
+
+// retryConfig converts the retry spec into the HTTP client configuration.
+// Without a spec, requests are attempted once but still reported to onAttempt.
+func retryConfig(spec *httpv1alpha1.HTTPRetrySpec, onAttempt func(util.RetryAttempt)) (*util.RetryConfig, error) {
+	if spec == nil {
+		return &util.RetryConfig{MaxAttempts: 1, OnAttempt: onAttempt}, nil
+	}
+
+	config := &util.RetryConfig{
+		MaxAttempts:          spec.MaxAttempts,
+		JitterPercent:        spec.JitterPercent,
+		RetryableStatusCodes: spec.RetryableStatusCodes,
+		OnAttempt:            onAttempt,
+	}
+	var err error
+	if config.BaseBackoff, err = parseDuration("retry.baseBackoff", spec.BaseBackoff); err != nil {
+		return nil, err
+	}
+	if config.MaxBackoff, err = parseDuration("retry.maxBackoff", spec.MaxBackoff); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseDuration parses an optional duration field, returning zero if it is empty
+func parseDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s '%s': %w", field, value, err)
+	}
+	return duration, nil
+}
+
 // processHTTPResponse processes the HTTP response and converts it to Kubernetes resources
-func (r *HTTPQueryResourceReconciler) processHTTPResponse(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, items []util.ItemResult) ([]*unstructured.Unstructured, error) {
+func (r *HTTPQueryResourceReconciler) processHTTPResponse(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, items []util.ItemResult, input util.TemplateInput) ([]*unstructured.Unstructured, error) {
 	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
 
 	// Initialize TemplateProcessor if not set
@@ -292,13 +709,30 @@ func (r *HTTPQueryResourceReconciler) processHTTPResponse(ctx context.Context, h
 	}
 
 	// Use TemplateProcessor to process items into resources
-	resources, err := r.TemplateProcessor.ProcessHTTPResponseToResources(httpQueryResource.Spec.Template, items)
-	if err != nil {
+	spec := &httpQueryResource.Spec
+	var resources []*unstructured.Unstructured
+	var err error
+	if spec.TemplateMode == TemplateModeAggregate {
+		resources, err = r.TemplateProcessor.ProcessAggregateToResources(spec.Template, items, input)
+	} else {
+		resources, err = r.TemplateProcessor.ProcessItemsToResources(spec.Template, items, input)
+	}
+	var templateErr *util.TemplateError
+	if err != nil && !errors.As(err, &templateErr) {
 		return nil, err
 	}
 
+	// Render the aggregate template alongside the per-item template
+	if spec.AggregateTemplate != "" && spec.TemplateMode != TemplateModeAggregate {
+		aggregated, aggregateErr := r.TemplateProcessor.ProcessAggregateToResources(spec.AggregateTemplate, items, input)
+		if aggregateErr != nil {
+			return nil, aggregateErr
+		}
+		resources = append(resources, aggregated...)
+	}
+
 	log.Info("Processed HTTP response", "itemCount", len(items), "resourceCount", len(resources))
-	return resources, nil
+	return resources, err
 }
 
 // applyResource applies a single resource to the cluster
@@ -390,8 +824,35 @@ func (r *HTTPQueryResourceReconciler) deleteOwnedResources(ctx context.Context,
 	return nil
 }
 
-// cleanupUnmanagedResources removes resources that are no longer managed
-func (r *HTTPQueryResourceReconciler) cleanupUnmanagedResources(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, currentResources []*unstructured.Unstructured) error {
+// listOwnedResources lists all resources of the owned GVKs that are owned by the HTTPQueryResource
+func (r *HTTPQueryResourceReconciler) listOwnedResources(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource) ([]*unstructured.Unstructured, error) {
+	var resources []*unstructured.Unstructured
+	for _, gvk := range r.OwnedGVKs {
+		list := &unstructured.UnstructuredList{}
+		list.SetAPIVersion(gvk.GroupVersion().String())
+		list.SetKind(gvk.Kind + "List")
+
+		listOpts := []client.ListOption{
+			client.InNamespace(httpQueryResource.GetNamespace()),
+			client.MatchingLabels{ManagedByLabel: ControllerName},
+		}
+
+		if err := r.List(ctx, list, listOpts...); err != nil {
+			return nil, fmt.Errorf("failed to list owned resources of %s: %w", gvk.String(), err)
+		}
+
+		for i := range list.Items {
+			if metav1.IsControlledBy(&list.Items[i], httpQueryResource) {
+				resources = append(resources, &list.Items[i])
+			}
+		}
+	}
+	return resources, nil
+}
+
+// cleanupUnmanagedResources removes resources that are no longer managed. Resources labeled with
+// one of the kept item keys are not removed.
+func (r *HTTPQueryResourceReconciler) cleanupUnmanagedResources(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, currentResources []*unstructured.Unstructured, keptItemKeys map[string]bool) error {
 	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
 
 	// Create a set of current resource names for quick lookup
@@ -433,6 +894,10 @@ func (r *HTTPQueryResourceReconciler) cleanupUnmanagedResources(ctx context.Cont
 
 			// Check if this resource is still in the current set
 			key := fmt.Sprintf("%s/%s/%s", item.GetAPIVersion(), item.GetKind(), item.GetName())
+			if !currentResourceNames[key] && keptItemKeys[item.GetLabels()[util.ItemKeyLabel]] {
+				log.Info("Keeping resource of failed item", "resource", item.GetName(), "gvk", gvk.String())
+				continue
+			}
 			if !currentResourceNames[key] {
 				log.Info("Deleting unmanaged resource", "resource", item.GetName(), "gvk", gvk.String())
 				if err := r.Delete(ctx, &item); err != nil && !apierrors.IsNotFound(err) {
@@ -449,14 +914,47 @@ func (r *HTTPQueryResourceReconciler) cleanupUnmanagedResources(ctx context.Cont
 func (r *HTTPQueryResourceReconciler) SetupWithManagerAndGVKs(mgr ctrl.Manager, ownedGVKs []schema.GroupVersionKind) error {
 	r.OwnedGVKs = ownedGVKs // Store the GVKs for use in reconciliation
 
+	// Index resources by referenced Secret, so that credential rotations are picked up immediately
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &httpv1alpha1.HTTPQueryResource{}, secretRefIndexKey, indexSecretRefs); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &httpv1alpha1.HTTPQueryResource{}, endpointRefIndexKey, indexEndpointRefs); err != nil {
+		return err
+	}
+
 	controllerBuilder := ctrl.NewControllerManagedBy(mgr).
-		For(&httpv1alpha1.HTTPQueryResource{})
+		For(&httpv1alpha1.HTTPQueryResource{}).
+		Watches(&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretToHTTPQueryResources),
+			builder.WithPredicates(secretDataChangedPredicate())).
+		// Status updates of endpoints are health checks, which do not change the requests
+		Watches(&httpv1alpha1.HTTPEndpoint{},
+			handler.EnqueueRequestsFromMapFunc(r.endpointToHTTPQueryResources),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&httpv1alpha1.ClusterHTTPEndpoint{},
+			handler.EnqueueRequestsFromMapFunc(r.endpointToHTTPQueryResources),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}))
+
+	if r.Webhooks != nil {
+		controllerBuilder = controllerBuilder.WatchesRawSource(source.Channel(r.Webhooks.Events(), &handler.EnqueueRequestForObject{}))
+	}
+
+	// Streams trigger reconciliations when their items change, and are stopped with the manager
+	r.streams = newStreamManager(mgr.GetLogger().WithName("streams"))
+	controllerBuilder = controllerBuilder.WatchesRawSource(source.Channel(r.streams.events, &handler.EnqueueRequestForObject{}))
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		r.streams.stopAll()
+		return nil
+	})); err != nil {
+		return err
+	}
 
 	// Custom event handler for owned resources
 	for _, gvk := range ownedGVKs {
 		u := &unstructured.Unstructured{}
 		u.SetGroupVersionKind(gvk)
-		controllerBuilder = controllerBuilder.Owns(u, builder.WithPredicates(
+		controllerBuilder = controllerBuilder.Watches(u, handler.EnqueueRequestsFromMapFunc(r.childToHTTPQueryResource), builder.WithPredicates(
 			statusChangePredicate(),
 			predicate.ResourceVersionChangedPredicate{},
 			predicate.GenerationChangedPredicate{},
@@ -489,12 +987,32 @@ func (r *HTTPQueryResourceReconciler) updateStatusForChildResources(ctx context.
 		return nil
 	}
 
+	retry, err := retryConfig(httpQueryResource.Spec.StatusUpdate.Retry, func(attempt util.RetryAttempt) {
+		if attempt.Backoff > 0 && httpQueryResource.Status.Requests != nil {
+			httpQueryResource.Status.Requests.StatusUpdateRetries++
+			httpQueryResource.Status.Requests.LastRetryReason = "status update: " + attempt.Reason()
+		}
+	})
+	if err != nil {
+		r.setCondition(httpQueryResource, ConditionReconciled, metav1.ConditionFalse, "StatusUpdateFailed", err.Error())
+		return err
+	}
+
+	timeout, err := parseDuration("statusUpdate.timeout", httpQueryResource.Spec.StatusUpdate.Timeout)
+	if err != nil {
+		r.setCondition(httpQueryResource, ConditionReconciled, metav1.ConditionFalse, "StatusUpdateFailed", err.Error())
+		return err
+	}
+
 	// Prepare base status config
 	statusConfig := util.HTTPStatusUpdateConfig{
-		URL:          httpQueryResource.Spec.StatusUpdate.URL,
-		Method:       httpQueryResource.Spec.StatusUpdate.Method,
-		Headers:      httpQueryResource.Spec.StatusUpdate.Headers,
-		BodyTemplate: httpQueryResource.Spec.StatusUpdate.BodyTemplate,
+		URL:              httpQueryResource.Spec.StatusUpdate.URL,
+		Method:           httpQueryResource.Spec.StatusUpdate.Method,
+		Headers:          httpQueryResource.Spec.StatusUpdate.Headers,
+		BodyTemplate:     httpQueryResource.Spec.StatusUpdate.BodyTemplate,
+		Retry:            retry,
+		Timeout:          timeout,
+		MaxResponseBytes: httpQueryResource.Spec.StatusUpdate.MaxResponseBytes,
 	}
 
 	// Resolve authentication for status updates
@@ -512,6 +1030,22 @@ func (r *HTTPQueryResourceReconciler) updateStatusForChildResources(ctx context.
 		}
 		statusConfig.AuthType = authConfig.AuthType
 		statusConfig.AuthConfig = authConfig.AuthConfig
+		statusConfig.OnRefreshToken = r.AuthResolver.RefreshTokenWriter(authConfig)
+	}
+
+	// Resolve TLS config for status updates
+	if httpQueryResource.Spec.StatusUpdate.TLS != nil {
+		if r.AuthResolver == nil {
+			r.AuthResolver = util.NewAuthResolver(r.Client, r.Log)
+		}
+
+		tlsConfig, err := r.AuthResolver.ResolveTLSConfig(ctx, httpQueryResource.Namespace, httpQueryResource.Spec.StatusUpdate.TLS)
+		if err != nil {
+			log.Error(err, "Failed to resolve status update TLS configuration")
+			r.setCondition(httpQueryResource, ConditionReconciled, metav1.ConditionFalse, "StatusUpdateFailed", "Failed to resolve status update TLS configuration: "+err.Error())
+			return err
+		}
+		statusConfig.TLS = tlsConfig
 	}
 
 	// Send status updates for each managed resource and track errors
@@ -548,10 +1082,12 @@ func (r *HTTPQueryResourceReconciler) updateStatusForChildResources(ctx context.
 			}
 		}
 
-		// Create enhanced template context with both resource and original item
+		// Create enhanced template context with both resource and original item. The item key
+		// lets the callback address the item, as its other fields may change.
 		templateData := map[string]interface{}{
 			"Resource": currentResource.Object,
 			"Item":     originalItem,
+			"ItemKey":  annotations[util.ItemKeyAnnotation],
 		}
 
 		// Execute status update with enhanced context
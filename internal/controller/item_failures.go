@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// Failure policies for the resources of items that fail to process
+const (
+	FailurePolicyPrune        = "prune"
+	FailurePolicyKeepExisting = "keepExisting"
+)
+
+const (
+	// maxReportedItemFailures is the number of failed items listed in the Degraded condition and Events
+	maxReportedItemFailures = 10
+	// maxConditionMessageLength is the maximum length of condition messages in the CRD
+	maxConditionMessageLength = 32768
+	// maxEventMessageLength is the maximum length of Event messages accepted by the events API
+	maxEventMessageLength = 1024
+)
+
+// recordItemFailures records the items that failed to process in the Degraded condition and a
+// Warning Event, which list the first failures, and logs every failure. A nil error clears the condition.
+func (r *HTTPQueryResourceReconciler) recordItemFailures(httpQueryResource *httpv1alpha1.HTTPQueryResource, templateErr *util.TemplateError) {
+	if templateErr == nil {
+		r.setCondition(httpQueryResource, ConditionDegraded, metav1.ConditionFalse, "AllItemsProcessed", "All items were processed successfully")
+		return
+	}
+	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
+	for _, failure := range templateErr.Failures {
+		log.Info("Item failed to process", "index", failure.Index, "key", failure.Key, "reason", fmt.Sprint(failure.Err))
+	}
+
+	message := itemFailuresMessage(templateErr)
+	r.setCondition(httpQueryResource, ConditionDegraded, metav1.ConditionTrue, "ItemsFailed", truncateMessage(message, maxConditionMessageLength))
+	if r.Recorder != nil {
+		r.Recorder.Event(httpQueryResource, corev1.EventTypeWarning, "ItemsFailed", truncateMessage(message, maxEventMessageLength))
+	}
+}
+
+// itemFailuresMessage summarizes the failed items, listing at most maxReportedItemFailures of them
+func itemFailuresMessage(templateErr *util.TemplateError) string {
+	failures := templateErr.Failures
+	listed := make([]string, 0, maxReportedItemFailures)
+	for _, failure := range failures[:min(len(failures), maxReportedItemFailures)] {
+		listed = append(listed, failure.Error())
+	}
+	message := fmt.Sprintf("%d of %d items failed to process: ", len(failures), templateErr.Total)
+	if len(failures) == templateErr.Total {
+		message = "all items failed to process: "
+	}
+	message += strings.Join(listed, "; ")
+	if len(failures) > maxReportedItemFailures {
+		message += fmt.Sprintf("; and %d more, see the operator logs", len(failures)-maxReportedItemFailures)
+	}
+	return message
+}
+
+// truncateMessage shortens a message to at most maxLength bytes, marking that it was truncated
+func truncateMessage(message string, maxLength int) string {
+	if len(message) <= maxLength {
+		return message
+	}
+	const suffix = "... (truncated, see the operator logs)"
+	end := maxLength - len(suffix)
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+	return message[:end] + suffix
+}
+
+// keptItemKeys returns the item keys, as label values, whose existing resources are not pruned
+// because their items failed to process under the keepExisting policy. Without an itemKey, or if
+// a failed item has no key, the resources of failed items cannot be told apart from the resources
+// of removed items, so keepAll reports that pruning is suspended.
+func keptItemKeys(httpQueryResource *httpv1alpha1.HTTPQueryResource, templateErr *util.TemplateError) (keys map[string]bool, keepAll bool) {
+	if templateErr == nil || httpQueryResource.Spec.FailurePolicy != FailurePolicyKeepExisting {
+		return nil, false
+	}
+	failedKeys, ok := templateErr.Keys()
+	if httpQueryResource.Spec.ItemKey == "" || !ok {
+		return nil, true
+	}
+	keys = make(map[string]bool, len(failedKeys))
+	for _, key := range failedKeys {
+		keys[util.ItemKeyLabelValue(key)] = true
+	}
+	return keys, false
+}
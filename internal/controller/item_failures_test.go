@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// staticHTTPClient returns the same items for every query
+type staticHTTPClient struct {
+	util.HTTPClient
+	items []util.ItemResult
+}
+
+func (c *staticHTTPClient) Query(ctx context.Context, config util.HTTPConfig) (*util.QueryResult, error) {
+	return &util.QueryResult{Items: c.items}, nil
+}
+
+func TestReconcileResources_ItemFailures(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, httpv1alpha1.AddToScheme(scheme))
+
+	// bob no longer has a name, so his ConfigMap is no longer rendered
+	httpClient := &staticHTTPClient{items: []util.ItemResult{{"id": "a", "name": "alice"}, {"id": "b"}}}
+
+	enabled := true
+	reconcile := func(t *testing.T, failurePolicy string) (*httpv1alpha1.HTTPQueryResource, client.Client, *record.FakeRecorder) {
+		httpQueryResource := &httpv1alpha1.HTTPQueryResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "team-a", UID: "users-uid"},
+			Spec: httpv1alpha1.HTTPQueryResourceSpec{
+				HTTP: httpv1alpha1.HTTPSpec{URL: "https://api.example.com/users"},
+				Template: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: user-{{ if not .Item.name }}{{ fail "name is required" }}{{ end }}{{ .Item.name }}`,
+				Prune:         &enabled,
+				FailurePolicy: failurePolicy,
+			},
+		}
+		existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      "user-bob",
+			Namespace: "team-a",
+			Labels:    map[string]string{ManagedByLabel: ControllerName},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: httpv1alpha1.GroupVersion.String(), Kind: "HTTPQueryResource", Name: "users", UID: "users-uid", Controller: &enabled,
+			}},
+		}}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &HTTPQueryResourceReconciler{
+			Client:    fakeClient,
+			Scheme:    scheme,
+			Log:       logr.Discard(),
+			OwnedGVKs: []schema.GroupVersionKind{corev1.SchemeGroupVersion.WithKind("ConfigMap")},
+			Recorder:  recorder,
+		}
+		_, err := r.reconcileResources(context.Background(), httpQueryResource, httpClient)
+		require.NoError(t, err)
+		return httpQueryResource, fakeClient, recorder
+	}
+
+	t.Run("prune", func(t *testing.T) {
+		httpQueryResource, fakeClient, recorder := reconcile(t, "")
+
+		degraded := meta.FindStatusCondition(httpQueryResource.Status.Conditions, ConditionDegraded)
+		require.NotNil(t, degraded)
+		assert.Equal(t, metav1.ConditionTrue, degraded.Status)
+		assert.Equal(t, "ItemsFailed", degraded.Reason)
+		assert.Contains(t, degraded.Message, "1 of 2 items failed to process: item 1:")
+		assert.Contains(t, <-recorder.Events, "Warning ItemsFailed 1 of 2 items failed to process")
+		assert.Equal(t, []string{"user-alice"}, httpQueryResource.Status.ManagedResources)
+		assert.Nil(t, httpQueryResource.Status.LastResponse)
+
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "user-bob"}, &corev1.ConfigMap{})
+		assert.True(t, client.IgnoreNotFound(err) == nil && err != nil, "resource of the failed item is pruned")
+	})
+
+	t.Run("keepExisting", func(t *testing.T) {
+		_, fakeClient, _ := reconcile(t, FailurePolicyKeepExisting)
+
+		assert.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "user-bob"}, &corev1.ConfigMap{}))
+		assert.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "user-alice"}, &corev1.ConfigMap{}))
+	})
+
+	t.Run("many failures", func(t *testing.T) {
+		templateErr := &util.TemplateError{Total: 1000, Failures: itemFailures(1000, strings.Repeat("x", 5000))}
+		httpQueryResource := &httpv1alpha1.HTTPQueryResource{}
+		recorder := record.NewFakeRecorder(1)
+		r := &HTTPQueryResourceReconciler{Log: logr.Discard(), Recorder: recorder}
+		r.recordItemFailures(httpQueryResource, templateErr)
+
+		degraded := meta.FindStatusCondition(httpQueryResource.Status.Conditions, ConditionDegraded)
+		require.NotNil(t, degraded)
+		assert.LessOrEqual(t, len(degraded.Message), maxConditionMessageLength)
+		assert.True(t, strings.HasPrefix(degraded.Message, "all items failed to process: item 0 (key 0): xxx"))
+		assert.Contains(t, degraded.Message, "item 5 (key 5)")
+		assert.NotContains(t, degraded.Message, "item 10 (key 10)")
+		assert.True(t, strings.HasSuffix(degraded.Message, "... (truncated, see the operator logs)"))
+
+		event := <-recorder.Events
+		assert.LessOrEqual(t, len(event), len("Warning ItemsFailed ")+maxEventMessageLength)
+
+		assert.Equal(t, "1000 of 2000 items failed to process: item 0 (key 0): a; item 1 (key 1): a; item 2 (key 2): a; "+
+			"item 3 (key 3): a; item 4 (key 4): a; item 5 (key 5): a; item 6 (key 6): a; item 7 (key 7): a; item 8 (key 8): a; "+
+			"item 9 (key 9): a; and 990 more, see the operator logs", itemFailuresMessage(&util.TemplateError{Total: 2000, Failures: itemFailures(1000, "a")}))
+	})
+
+	t.Run("recovered", func(t *testing.T) {
+		httpQueryResource := &httpv1alpha1.HTTPQueryResource{}
+		r := &HTTPQueryResourceReconciler{}
+		r.recordItemFailures(httpQueryResource, &util.TemplateError{Total: 1, Failures: []util.ItemError{{Index: 0}}})
+		r.recordItemFailures(httpQueryResource, nil)
+		assert.True(t, meta.IsStatusConditionFalse(httpQueryResource.Status.Conditions, ConditionDegraded))
+	})
+}
+
+// itemFailures returns n item failures keyed by their index, failing for the reason
+func itemFailures(n int, reason string) []util.ItemError {
+	failures := make([]util.ItemError, 0, n)
+	for i := range n {
+		failures = append(failures, util.ItemError{Index: i, Key: strconv.Itoa(i), Err: errors.New(reason)})
+	}
+	return failures
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// itemResourceKey identifies the resources of a kind rendered for an item
+type itemResourceKey struct {
+	gvk     schema.GroupVersionKind
+	itemKey string
+}
+
+// keepResourceNames renames the rendered resources to the names of the existing resources of
+// their items, so that a changed name, e.g. one derived from a renamed display field, does not
+// delete and recreate the resource. Only an item with a single resource of a kind is matched.
+func (r *HTTPQueryResourceReconciler) keepResourceNames(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, resources []*unstructured.Unstructured) error {
+	log := r.Log.WithValues("httpqueryresource", httpQueryResource.Name)
+
+	existing, err := r.listOwnedResources(ctx, httpQueryResource)
+	if err != nil {
+		return err
+	}
+	existingNames := map[itemResourceKey][]string{}
+	for _, resource := range existing {
+		if key, ok := resource.GetLabels()[util.ItemKeyLabel]; ok {
+			ref := itemResourceKey{gvk: resource.GroupVersionKind(), itemKey: key}
+			existingNames[ref] = append(existingNames[ref], resource.GetName())
+		}
+	}
+
+	rendered := map[itemResourceKey][]*unstructured.Unstructured{}
+	renderedNames := map[schema.GroupVersionKind]map[string]bool{}
+	for _, resource := range resources {
+		gvk := resource.GroupVersionKind()
+		if renderedNames[gvk] == nil {
+			renderedNames[gvk] = map[string]bool{}
+		}
+		renderedNames[gvk][resource.GetName()] = true
+		if resource.GetNamespace() != "" && resource.GetNamespace() != httpQueryResource.Namespace {
+			continue
+		}
+		if key, ok := resource.GetLabels()[util.ItemKeyLabel]; ok {
+			ref := itemResourceKey{gvk: gvk, itemKey: key}
+			rendered[ref] = append(rendered[ref], resource)
+		}
+	}
+
+	for ref, itemResources := range rendered {
+		names := existingNames[ref]
+		if len(itemResources) != 1 || len(names) != 1 {
+			continue
+		}
+		resource, name := itemResources[0], names[0]
+		// Do not overwrite another rendered resource
+		if resource.GetName() == name || renderedNames[ref.gvk][name] {
+			continue
+		}
+		log.Info("Keeping the name of the existing resource of the item", "itemKey", resource.GetAnnotations()[util.ItemKeyAnnotation], "renderedName", resource.GetName(), "resource", name)
+		resource.SetName(name)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+func TestReconcileResources_ItemKey(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, httpv1alpha1.AddToScheme(scheme))
+
+	enabled := true
+	existingResource := func(name, itemKey string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "team-a",
+			Labels:      map[string]string{ManagedByLabel: ControllerName, util.ItemKeyLabel: util.ItemKeyLabelValue(itemKey)},
+			Annotations: map[string]string{util.ItemKeyAnnotation: itemKey},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: httpv1alpha1.GroupVersion.String(), Kind: "HTTPQueryResource", Name: "users", UID: "users-uid", Controller: &enabled,
+			}},
+		}}
+	}
+	// Names are derived from the display name, which alice changed to "alicia"
+	reconcile := func(t *testing.T, failurePolicy string, items []util.ItemResult) (*httpv1alpha1.HTTPQueryResource, client.Client) {
+		httpQueryResource := &httpv1alpha1.HTTPQueryResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "team-a", UID: "users-uid"},
+			Spec: httpv1alpha1.HTTPQueryResourceSpec{
+				HTTP: httpv1alpha1.HTTPSpec{URL: "https://api.example.com/users"},
+				Template: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: user-{{ if not .Item.name }}{{ fail "name is required" }}{{ end }}{{ .Item.name }}
+data:
+  name: "{{ .Item.name }}"`,
+				ItemKey:       "id",
+				Prune:         &enabled,
+				FailurePolicy: failurePolicy,
+			},
+		}
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			existingResource("user-alice", "a"),
+			existingResource("user-bob", "b"),
+			existingResource("user-carol", "c"),
+		).Build()
+		r := &HTTPQueryResourceReconciler{
+			Client:    fakeClient,
+			Scheme:    scheme,
+			Log:       logr.Discard(),
+			OwnedGVKs: []schema.GroupVersionKind{corev1.SchemeGroupVersion.WithKind("ConfigMap")},
+		}
+		_, err := r.reconcileResources(context.Background(), httpQueryResource, &staticHTTPClient{items: items})
+		require.NoError(t, err)
+		return httpQueryResource, fakeClient
+	}
+	exists := func(t *testing.T, c client.Client, name string) bool {
+		err := c.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: name}, &corev1.ConfigMap{})
+		require.NoError(t, client.IgnoreNotFound(err))
+		return err == nil
+	}
+
+	t.Run("renamed item keeps its resource", func(t *testing.T) {
+		httpQueryResource, fakeClient := reconcile(t, "", []util.ItemResult{
+			{"id": "a", "name": "alicia"}, {"id": "b", "name": "bob"},
+		})
+
+		assert.Equal(t, []string{"user-alice", "user-bob"}, httpQueryResource.Status.ManagedResources)
+		assert.False(t, exists(t, fakeClient, "user-alicia"))
+		assert.False(t, exists(t, fakeClient, "user-carol"), "resource of the removed item is pruned")
+
+		configMap := &corev1.ConfigMap{}
+		require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: "user-alice"}, configMap))
+		assert.Equal(t, "alicia", configMap.Data["name"])
+		assert.Equal(t, "a", configMap.Labels[util.ItemKeyLabel])
+	})
+
+	t.Run("keepExisting keeps only the resources of failed items", func(t *testing.T) {
+		httpQueryResource, fakeClient := reconcile(t, FailurePolicyKeepExisting, []util.ItemResult{
+			{"id": "a", "name": "alice"}, {"id": "b"},
+		})
+
+		assert.True(t, exists(t, fakeClient, "user-bob"), "resource of the failed item is kept")
+		assert.False(t, exists(t, fakeClient, "user-carol"), "resource of the removed item is pruned")
+		degraded := meta.FindStatusCondition(httpQueryResource.Status.Conditions, ConditionDegraded)
+		require.NotNil(t, degraded)
+		assert.Contains(t, degraded.Message, "item 1 (key b): template error")
+	})
+
+	t.Run("keepExisting keeps all resources if a failed item has no key", func(t *testing.T) {
+		_, fakeClient := reconcile(t, FailurePolicyKeepExisting, []util.ItemResult{
+			{"id": "a", "name": "alice"}, {"name": "bob"},
+		})
+
+		assert.True(t, exists(t, fakeClient, "user-bob"))
+		assert.True(t, exists(t, fakeClient, "user-carol"))
+	})
+}
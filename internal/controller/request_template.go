@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// renderedRequest holds the rendered URL, headers and body of a request
+type renderedRequest struct {
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+// requestTemplateData builds the template context of the poll request:
+//   - .Resource: the HTTPQueryResource as a map (metadata, spec and status)
+//   - .LastPollTime: the time of the last successful poll in RFC 3339 format, or "" before the first poll
+//   - .Values: the resolved values of the spec
+func (r *HTTPQueryResourceReconciler) requestTemplateData(ctx context.Context, httpQueryResource *httpv1alpha1.HTTPQueryResource, values []httpv1alpha1.HTTPTemplateValue) (map[string]interface{}, error) {
+	resource, err := runtime.DefaultUnstructuredConverter.ToUnstructured(httpQueryResource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert HTTPQueryResource for templating: %w", err)
+	}
+
+	lastPollTime := ""
+	if httpQueryResource.Status.LastPollTime != nil {
+		lastPollTime = httpQueryResource.Status.LastPollTime.UTC().Format(time.RFC3339)
+	}
+
+	resolvedValues := map[string]string{}
+	if len(values) > 0 {
+		if r.AuthResolver == nil {
+			r.AuthResolver = util.NewAuthResolver(r.Client, r.Log)
+		}
+		resolvedValues, err = r.AuthResolver.ResolveTemplateValues(ctx, httpQueryResource.Namespace, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve template values: %w", err)
+		}
+	}
+
+	return map[string]interface{}{
+		"Resource":     resource,
+		"LastPollTime": lastPollTime,
+		"Values":       resolvedValues,
+	}, nil
+}
+
+// renderRequest renders the URL, header values and body of a request with the given template context
+func (r *HTTPQueryResourceReconciler) renderRequest(url string, headers map[string]string, body string, data map[string]interface{}) (*renderedRequest, error) {
+	// Initialize TemplateProcessor if not set
+	if r.TemplateProcessor == nil {
+		r.TemplateProcessor = util.NewTemplateProcessor()
+	}
+
+	renderedURL, err := r.TemplateProcessor.ProcessTemplate(url, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render URL: %w", err)
+	}
+
+	var renderedHeaders map[string]string
+	if headers != nil {
+		renderedHeaders = make(map[string]string, len(headers))
+		for key, value := range headers {
+			renderedValue, err := r.TemplateProcessor.ProcessTemplate(value, data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to render header '%s': %w", key, err)
+			}
+			renderedHeaders[key] = renderedValue
+		}
+	}
+
+	renderedBody, err := r.TemplateProcessor.ProcessTemplate(body, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render body: %w", err)
+	}
+
+	return &renderedRequest{URL: renderedURL, Headers: renderedHeaders, Body: renderedBody}, nil
+}
+
+// renderGraphQL renders the variables of a GraphQL query with the given template context
+func (r *HTTPQueryResourceReconciler) renderGraphQL(spec *httpv1alpha1.HTTPGraphQLSpec, data map[string]interface{}) (*util.GraphQLConfig, error) {
+	if r.TemplateProcessor == nil {
+		r.TemplateProcessor = util.NewTemplateProcessor()
+	}
+
+	config := &util.GraphQLConfig{
+		Query:         spec.Query,
+		OperationName: spec.OperationName,
+	}
+	if spec.Variables != "" {
+		renderedVariables, err := r.TemplateProcessor.ProcessTemplate(spec.Variables, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render GraphQL variables: %w", err)
+		}
+		if err := json.Unmarshal([]byte(renderedVariables), &config.Variables); err != nil {
+			return nil, fmt.Errorf("GraphQL variables are not a JSON object: %w", err)
+		}
+	}
+	if spec.Pagination != nil {
+		config.Pagination = &util.GraphQLPaginationConfig{
+			ConnectionPath: spec.Pagination.ConnectionPath,
+			CursorVariable: spec.Pagination.CursorVariable,
+			MaxPages:       spec.Pagination.MaxPages,
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+func TestRenderRequest(t *testing.T) {
+	r := &HTTPQueryResourceReconciler{}
+	lastPoll := metav1.NewTime(time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC))
+	httpQueryResource := &httpv1alpha1.HTTPQueryResource{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "users",
+			Namespace: "team-a",
+			Labels:    map[string]string{"tenant": "acme"},
+		},
+		Status: httpv1alpha1.HTTPQueryResourceStatus{LastPollTime: &lastPoll},
+	}
+
+	data, err := r.requestTemplateData(context.Background(), httpQueryResource, []httpv1alpha1.HTTPTemplateValue{
+		{Name: "pageSize", Value: "50"},
+	})
+	require.NoError(t, err)
+
+	request, err := r.renderRequest(
+		"https://api.example.com/{{ .Resource.metadata.namespace }}/users?since={{ .LastPollTime }}&limit={{ .Values.pageSize }}",
+		map[string]string{"X-Tenant": `{{ index .Resource.metadata.labels "tenant" }}`},
+		`{"name": "{{ .Resource.metadata.name }}"}`,
+		data)
+	require.NoError(t, err)
+	assert.Equal(t, "https://api.example.com/team-a/users?since=2026-10-01T12:30:00Z&limit=50", request.URL)
+	assert.Equal(t, map[string]string{"X-Tenant": "acme"}, request.Headers)
+	assert.Equal(t, `{"name": "users"}`, request.Body)
+
+	t.Run("first poll", func(t *testing.T) {
+		data, err := r.requestTemplateData(context.Background(), &httpv1alpha1.HTTPQueryResource{}, nil)
+		require.NoError(t, err)
+		request, err := r.renderRequest(`https://api.example.com/users{{ with .LastPollTime }}?since={{ . }}{{ end }}`, nil, "", data)
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.example.com/users", request.URL)
+		assert.Nil(t, request.Headers)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := r.renderRequest("https://api.example.com", map[string]string{"X-Bad": "{{ .Missing"}, "", data)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to render header 'X-Bad'")
+	})
+}
+
+func TestRenderGraphQL(t *testing.T) {
+	r := &HTTPQueryResourceReconciler{}
+	data := map[string]interface{}{"Values": map[string]string{"team": "platform"}}
+
+	config, err := r.renderGraphQL(&httpv1alpha1.HTTPGraphQLSpec{
+		Query:      "query($team: String!, $first: Int) { users(team: $team, first: $first) { id } }",
+		Variables:  `{"team": "{{ .Values.team }}", "first": 50}`,
+		Pagination: &httpv1alpha1.HTTPGraphQLPaginationSpec{ConnectionPath: "data.users"},
+	}, data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"team": "platform", "first": float64(50)}, config.Variables)
+	assert.Equal(t, "data.users", config.Pagination.ConnectionPath)
+
+	t.Run("variables must be a JSON object", func(t *testing.T) {
+		_, err := r.renderGraphQL(&httpv1alpha1.HTTPGraphQLSpec{Query: "{ users { id } }", Variables: `["platform"]`}, data)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "GraphQL variables are not a JSON object")
+	})
+}
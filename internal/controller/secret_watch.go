@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"context"
+	"maps"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// secretRefIndexKey indexes HTTPQueryResources by the namespace/name of every Secret they reference
+const secretRefIndexKey = ".spec.secretRefs"
+
+// secretRefSet collects the namespace/name of referenced Secrets without duplicates
+type secretRefSet struct {
+	// namespace is the default namespace of references without one
+	namespace string
+	seen      map[string]bool
+	refs      []string
+}
+
+func newSecretRefSet(namespace string) *secretRefSet {
+	return &secretRefSet{namespace: namespace, seen: map[string]bool{}}
+}
+
+func (s *secretRefSet) add(name, namespace string) {
+	if namespace == "" {
+		namespace = s.namespace
+	}
+	ref := types.NamespacedName{Namespace: namespace, Name: name}.String()
+	if name != "" && !s.seen[ref] {
+		s.seen[ref] = true
+		s.refs = append(s.refs, ref)
+	}
+}
+
+func (s *secretRefSet) addAuth(authRef *httpv1alpha1.HTTPAuthenticationRef) {
+	// ServiceAccount tokens are minted, not read from a Secret
+	if authRef != nil && authRef.Type != "serviceAccountToken" {
+		s.add(authRef.Name, authRef.Namespace)
+	}
+}
+
+func (s *secretRefSet) addTLS(tls *httpv1alpha1.HTTPTLSSpec) {
+	if tls == nil {
+		return
+	}
+	if tls.CARef != nil && tls.CARef.Kind == "Secret" {
+		s.add(tls.CARef.Name, tls.CARef.Namespace)
+	}
+	if tls.ClientCertRef != nil {
+		s.add(tls.ClientCertRef.Name, tls.ClientCertRef.Namespace)
+	}
+}
+
+func (s *secretRefSet) addHTTP(spec *httpv1alpha1.HTTPSpec) {
+	s.addAuth(spec.AuthenticationRef)
+	s.addTLS(spec.TLS)
+	for _, value := range spec.Values {
+		if value.SecretKeyRef != nil {
+			s.add(value.SecretKeyRef.Name, value.SecretKeyRef.Namespace)
+		}
+	}
+}
+
+// referencedSecrets returns the namespace/name of every Secret referenced by the HTTPQueryResource
+// for authentication, TLS or template values, without duplicates. Secrets referenced through an
+// endpoint are indexed on the endpoint.
+func referencedSecrets(httpQueryResource *httpv1alpha1.HTTPQueryResource) []string {
+	refs := newSecretRefSet(httpQueryResource.Namespace)
+	refs.addHTTP(&httpQueryResource.Spec.HTTP)
+	for i := range httpQueryResource.Spec.Sources {
+		refs.addHTTP(&httpQueryResource.Spec.Sources[i].HTTPSpec)
+	}
+	if statusUpdate := httpQueryResource.Spec.StatusUpdate; statusUpdate != nil {
+		refs.addAuth(statusUpdate.AuthenticationRef)
+		refs.addTLS(statusUpdate.TLS)
+	}
+	return refs.refs
+}
+
+// endpointSecrets returns the namespace/name of every Secret referenced by an endpoint.
+// References of a ClusterHTTPEndpoint, whose namespace is empty, must set their namespace.
+func endpointSecrets(namespace string, spec *httpv1alpha1.HTTPEndpointSpec) []string {
+	refs := newSecretRefSet(namespace)
+	refs.addAuth(spec.AuthenticationRef)
+	refs.addTLS(spec.TLS)
+	return refs.refs
+}
+
+// indexSecretRefs is the index function of secretRefIndexKey
+func indexSecretRefs(obj client.Object) []string {
+	httpQueryResource, ok := obj.(*httpv1alpha1.HTTPQueryResource)
+	if !ok {
+		return nil
+	}
+	return referencedSecrets(httpQueryResource)
+}
+
+// secretToHTTPQueryResources maps a changed Secret to the HTTPQueryResources referencing it,
+// directly or through an endpoint. Tokens cached from the credentials of the Secret are discarded,
+// so that the next poll authenticates with the current credentials.
+func (r *HTTPQueryResourceReconciler) secretToHTTPQueryResources(ctx context.Context, obj client.Object) []reconcile.Request {
+	secretKey := client.ObjectKeyFromObject(obj)
+	log := r.Log.WithValues("secret", secretKey.String())
+
+	var list httpv1alpha1.HTTPQueryResourceList
+	if err := r.List(ctx, &list, client.MatchingFields{secretRefIndexKey: secretKey.String()}); err != nil {
+		log.Error(err, "Failed to list HTTPQueryResources referencing secret")
+		return nil
+	}
+	items := list.Items
+	for _, endpointRef := range r.endpointsReferencingSecret(ctx, secretKey) {
+		var referencing httpv1alpha1.HTTPQueryResourceList
+		if err := r.List(ctx, &referencing, client.MatchingFields{endpointRefIndexKey: endpointRef}); err != nil {
+			log.Error(err, "Failed to list HTTPQueryResources referencing endpoint", "endpoint", endpointRef)
+			continue
+		}
+		items = append(items, referencing.Items...)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	if r.HTTPClientFactory != nil {
+		if httpClient, err := r.HTTPClientFactory(ctx); err == nil {
+			if invalidator, ok := httpClient.(util.SecretInvalidator); ok {
+				invalidator.InvalidateSecret(secretKey)
+			}
+		}
+	}
+
+	requests := uniqueRequests(items)
+	log.Info("Referenced secret changed, reconciling HTTPQueryResources", "count", len(requests))
+	return requests
+}
+
+// endpointsReferencingSecret returns the endpointRefIndexKey values of the endpoints referencing the Secret.
+// The endpoint indexes are registered by the HTTPEndpointReconcilers, so none are found without them.
+func (r *HTTPQueryResourceReconciler) endpointsReferencingSecret(ctx context.Context, secretKey types.NamespacedName) []string {
+	var refs []string
+	var endpoints httpv1alpha1.HTTPEndpointList
+	if err := r.List(ctx, &endpoints, client.MatchingFields{secretRefIndexKey: secretKey.String()}); err == nil {
+		for _, endpoint := range endpoints.Items {
+			refs = append(refs, endpointRefKey(HTTPEndpointKind, endpoint.Namespace, endpoint.Name))
+		}
+	}
+	var clusterEndpoints httpv1alpha1.ClusterHTTPEndpointList
+	if err := r.List(ctx, &clusterEndpoints, client.MatchingFields{secretRefIndexKey: secretKey.String()}); err == nil {
+		for _, endpoint := range clusterEndpoints.Items {
+			refs = append(refs, endpointRefKey(ClusterHTTPEndpointKind, "", endpoint.Name))
+		}
+	}
+	return refs
+}
+
+// uniqueRequests returns a reconcile request for every HTTPQueryResource, without duplicates
+func uniqueRequests(items []httpv1alpha1.HTTPQueryResource) []reconcile.Request {
+	seen := map[types.NamespacedName]bool{}
+	requests := make([]reconcile.Request, 0, len(items))
+	for _, item := range items {
+		key := client.ObjectKeyFromObject(&item)
+		if !seen[key] {
+			seen[key] = true
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+	}
+	return requests
+}
+
+// secretDataChangedPredicate ignores Secret updates that do not change its data, e.g. metadata only updates,
+// and the write-back of rotated OAuth2 refresh tokens, which would otherwise discard the new tokens and
+// trigger the next rotation.
+func secretDataChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, oldOK := e.ObjectOld.(*corev1.Secret)
+			newSecret, newOK := e.ObjectNew.(*corev1.Secret)
+			if !oldOK || !newOK {
+				return true
+			}
+			if key, ok := util.WrittenBackRefreshTokenKey(newSecret); ok {
+				return !reflect.DeepEqual(withoutKey(oldSecret.Data, key), withoutKey(newSecret.Data, key))
+			}
+			return !reflect.DeepEqual(oldSecret.Data, newSecret.Data)
+		},
+	}
+}
+
+// withoutKey returns a copy of data without key
+func withoutKey(data map[string][]byte, key string) map[string][]byte {
+	data = maps.Clone(data)
+	delete(data, key)
+	return data
+}
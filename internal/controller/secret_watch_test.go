@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// invalidatingHTTPClient records the Secrets invalidated through util.SecretInvalidator
+type invalidatingHTTPClient struct {
+	util.HTTPClient
+	invalidated []types.NamespacedName
+}
+
+func (c *invalidatingHTTPClient) InvalidateSecret(secret types.NamespacedName) {
+	c.invalidated = append(c.invalidated, secret)
+}
+
+func TestReferencedSecrets(t *testing.T) {
+	httpQueryResource := &httpv1alpha1.HTTPQueryResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "team-a"},
+		Spec: httpv1alpha1.HTTPQueryResourceSpec{
+			HTTP: httpv1alpha1.HTTPSpec{
+				AuthenticationRef: &httpv1alpha1.HTTPAuthenticationRef{Name: "api-credentials", Type: "oauth2"},
+				TLS: &httpv1alpha1.HTTPTLSSpec{
+					CARef:         &httpv1alpha1.HTTPTLSCARef{Kind: "ConfigMap", Name: "ca-bundle"},
+					ClientCertRef: &httpv1alpha1.HTTPTLSClientCertRef{Name: "client-cert", Namespace: "shared"},
+				},
+				Values: []httpv1alpha1.HTTPTemplateValue{
+					{Name: "tenant", SecretKeyRef: &httpv1alpha1.HTTPKeyRef{Name: "api-credentials", Key: "tenant"}},
+				},
+			},
+			Sources: []httpv1alpha1.HTTPSourceSpec{{
+				Name: "groups",
+				HTTPSpec: httpv1alpha1.HTTPSpec{
+					AuthenticationRef: &httpv1alpha1.HTTPAuthenticationRef{Name: "poller", Type: "serviceAccountToken", Audience: "https://inventory.team-b.svc"},
+					TLS:               &httpv1alpha1.HTTPTLSSpec{CARef: &httpv1alpha1.HTTPTLSCARef{Kind: "Secret", Name: "groups-ca"}},
+				},
+			}},
+			StatusUpdate: &httpv1alpha1.HTTPStatusUpdateSpec{
+				AuthenticationRef: &httpv1alpha1.HTTPAuthenticationRef{Name: "callback-token", Namespace: "shared", Type: "bearer"},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"team-a/api-credentials",
+		"shared/client-cert",
+		"team-a/groups-ca",
+		"shared/callback-token",
+	}, referencedSecrets(httpQueryResource))
+}
+
+func TestSecretToHTTPQueryResources(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, httpv1alpha1.AddToScheme(scheme))
+
+	withAuth := func(name, secret string) *httpv1alpha1.HTTPQueryResource {
+		return &httpv1alpha1.HTTPQueryResource{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+			Spec: httpv1alpha1.HTTPQueryResourceSpec{HTTP: httpv1alpha1.HTTPSpec{
+				AuthenticationRef: &httpv1alpha1.HTTPAuthenticationRef{Name: secret, Type: "bearer"},
+			}},
+		}
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(withAuth("users", "api-token"), withAuth("groups", "api-token"), withAuth("orders", "other-token")).
+		WithIndex(&httpv1alpha1.HTTPQueryResource{}, secretRefIndexKey, indexSecretRefs).
+		Build()
+
+	httpClient := &invalidatingHTTPClient{}
+	r := &HTTPQueryResourceReconciler{
+		Client: fakeClient,
+		Log:    logr.Discard(),
+		HTTPClientFactory: func(ctx context.Context) (util.HTTPClient, error) {
+			return httpClient, nil
+		},
+	}
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "team-a"}}
+	requests := r.secretToHTTPQueryResources(context.Background(), secret)
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "users"}},
+		{NamespacedName: types.NamespacedName{Namespace: "team-a", Name: "groups"}},
+	}, requests)
+	assert.Equal(t, []types.NamespacedName{{Namespace: "team-a", Name: "api-token"}}, httpClient.invalidated)
+
+	t.Run("unreferenced secret", func(t *testing.T) {
+		unreferenced := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "api-token", Namespace: "team-b"}}
+		assert.Empty(t, r.secretToHTTPQueryResources(context.Background(), unreferenced))
+		assert.Len(t, httpClient.invalidated, 1)
+	})
+}
+
+func TestSecretDataChangedPredicate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	secretKey := client.ObjectKey{Namespace: "team-a", Name: "oauth"}
+	original := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "oauth", Namespace: "team-a"},
+		Data:       map[string][]byte{"clientId": []byte("client"), "refreshToken": []byte("seed")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(original.DeepCopy()).Build()
+	resolver := util.NewAuthResolver(fakeClient, logr.Discard())
+	write := resolver.RefreshTokenWriter(&util.ResolvedAuthConfig{AuthType: "oauth2", AuthConfig: map[string]string{
+		"secretRef":       secretKey.String(),
+		"refreshTokenKey": "refreshToken",
+	}})
+	get := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		require.NoError(t, fakeClient.Get(context.Background(), secretKey, secret))
+		return secret
+	}
+	changed := func(oldSecret, newSecret *corev1.Secret) bool {
+		return secretDataChangedPredicate().Update(event.UpdateEvent{ObjectOld: oldSecret, ObjectNew: newSecret})
+	}
+
+	before := get()
+	write("rotated")
+	written := get()
+	assert.False(t, changed(before, written), "write-back of a rotated refresh token")
+
+	manual := written.DeepCopy()
+	manual.Data["refreshToken"] = []byte("replaced")
+	assert.True(t, changed(written, manual), "refresh token replaced in the Secret")
+
+	otherKey := written.DeepCopy()
+	otherKey.Data["clientId"] = []byte("other-client")
+	assert.True(t, changed(before, otherKey), "other keys changed together with the write-back")
+
+	metadataOnly := written.DeepCopy()
+	metadataOnly.Labels = map[string]string{"team": "a"}
+	assert.False(t, changed(written, metadataOnly))
+}
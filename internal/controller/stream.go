@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// DefaultStreamDebounce is how long stream changes are collected before reconciling by default
+const DefaultStreamDebounce = time.Second
+
+// errStreamPending is returned while a stream has not delivered any events yet
+var errStreamPending = errors.New("waiting for the stream to deliver its items")
+
+// streamManager holds the streams of HTTPQueryResources in stream mode. Streams outlive
+// reconciliations and trigger one when their item set changes.
+type streamManager struct {
+	log     logr.Logger
+	events  chan event.GenericEvent
+	mu      sync.Mutex
+	streams map[types.NamespacedName]*itemStream
+}
+
+func newStreamManager(log logr.Logger) *streamManager {
+	return &streamManager{
+		log:     log,
+		events:  make(chan event.GenericEvent),
+		streams: map[types.NamespacedName]*itemStream{},
+	}
+}
+
+// itemStream is the stream of a single HTTPQueryResource with the item set folded from its events
+type itemStream struct {
+	// fingerprint of the configuration the stream was started with
+	fingerprint string
+	cancel      context.CancelFunc
+
+	mu    sync.Mutex
+	items *util.StreamItemSet
+	// ready is set once the first events have been debounced
+	ready bool
+	// err is the reason the connection was last lost
+	err   error
+	timer *time.Timer
+}
+
+// ensure starts the stream of an HTTPQueryResource, restarting it when its configuration changed
+func (m *streamManager) ensure(httpQueryResource *httpv1alpha1.HTTPQueryResource, httpClient util.HTTPClient, config util.HTTPConfig, debounce time.Duration) (*itemStream, error) {
+	key := client.ObjectKeyFromObject(httpQueryResource)
+	fingerprint, err := streamFingerprint(httpQueryResource, config)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.streams[key]; ok {
+		if existing.fingerprint == fingerprint {
+			return existing, nil
+		}
+		existing.stop()
+	}
+
+	spec := httpQueryResource.Spec.HTTP.Stream
+	typeField := spec.TypeField
+	if typeField == "" && spec.Format == util.StreamFormatNDJSON {
+		typeField = util.DefaultStreamTypeField
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = log.IntoContext(ctx, m.log.WithValues("httpqueryresource", key.String()))
+	stream := &itemStream{
+		fingerprint: fingerprint,
+		cancel:      cancel,
+		items: &util.StreamItemSet{
+			KeyField:      spec.KeyField,
+			TypeField:     typeField,
+			DataPath:      spec.DataPath,
+			DeleteTypes:   spec.DeleteTypes,
+			SnapshotTypes: spec.SnapshotTypes,
+		},
+	}
+	// Triggered reconciliations only need the key of the resource
+	trigger := func() {
+		stream.mu.Lock()
+		stream.timer = nil
+		stream.ready = true
+		stream.mu.Unlock()
+		select {
+		case m.events <- event.GenericEvent{Object: &httpv1alpha1.HTTPQueryResource{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}}:
+		case <-ctx.Done():
+		}
+	}
+	schedule := func() {
+		if stream.timer == nil {
+			stream.timer = time.AfterFunc(debounce, trigger)
+		}
+	}
+
+	config.Stream = &util.StreamConfig{
+		Format: spec.Format,
+		OnConnect: func() {
+			stream.mu.Lock()
+			defer stream.mu.Unlock()
+			stream.err = nil
+		},
+		OnDisconnect: func(err error) {
+			stream.mu.Lock()
+			defer stream.mu.Unlock()
+			stream.err = err
+		},
+	}
+	go func() {
+		err := httpClient.Stream(ctx, config, func(e util.StreamEvent) {
+			stream.mu.Lock()
+			defer stream.mu.Unlock()
+			changed, err := stream.items.Apply(e)
+			if err != nil {
+				m.log.Info("Ignoring invalid stream event", "httpqueryresource", key.String(), "id", e.ID, "reason", err.Error())
+				return
+			}
+			// The first events make the stream ready, even if they did not change the set
+			if changed || !stream.ready {
+				schedule()
+			}
+		})
+		if err != nil && !errors.Is(err, context.Canceled) {
+			stream.mu.Lock()
+			stream.err = err
+			stream.mu.Unlock()
+		}
+	}()
+
+	m.streams[key] = stream
+	m.log.Info("Started stream", "httpqueryresource", key.String(), "url", config.URL)
+	return stream, nil
+}
+
+// stop stops the stream of an HTTPQueryResource, if any
+func (m *streamManager) stop(key types.NamespacedName) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if stream, ok := m.streams[key]; ok {
+		stream.stop()
+		delete(m.streams, key)
+		m.log.Info("Stopped stream", "httpqueryresource", key.String())
+	}
+}
+
+// stopAll stops all streams
+func (m *streamManager) stopAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, stream := range m.streams {
+		stream.stop()
+		delete(m.streams, key)
+	}
+}
+
+func (s *itemStream) stop() {
+	s.cancel()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+}
+
+// snapshot returns the current items of the stream and whether a snapshot event confirmed that
+// they are complete, or errStreamPending before any events have been delivered
+func (s *itemStream) snapshot() ([]util.ItemResult, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ready {
+		if s.err != nil {
+			return nil, false, fmt.Errorf("stream not connected: %w", s.err)
+		}
+		return nil, false, errStreamPending
+	}
+	return s.items.Items(), s.items.Complete(), nil
+}
+
+// streamFingerprint identifies the configuration of a stream, so that it is restarted when the spec
+// or the resolved credentials change
+func streamFingerprint(httpQueryResource *httpv1alpha1.HTTPQueryResource, config util.HTTPConfig) (string, error) {
+	data, err := json.Marshal(struct {
+		Spec       *httpv1alpha1.HTTPSpec
+		URL        string
+		Headers    map[string]string
+		Body       string
+		AuthConfig map[string]string
+		TLS        *util.ResolvedTLSConfig
+	}{&httpQueryResource.Spec.HTTP, config.URL, config.Headers, config.Body, config.AuthConfig, config.TLS})
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint stream configuration: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// streamItems returns the items folded from the stream of the HTTPQueryResource, starting the stream
+// if needed, and whether they are complete, see itemStream.snapshot
+func (r *HTTPQueryResourceReconciler) streamItems(httpQueryResource *httpv1alpha1.HTTPQueryResource, httpClient util.HTTPClient, config util.HTTPConfig) ([]util.ItemResult, bool, error) {
+	debounce, err := parseDuration("http.stream.debounce", httpQueryResource.Spec.HTTP.Stream.Debounce)
+	if err != nil {
+		return nil, false, err
+	}
+	if debounce == 0 {
+		debounce = DefaultStreamDebounce
+	}
+	if r.streams == nil {
+		r.streams = newStreamManager(r.Log)
+	}
+	stream, err := r.streams.ensure(httpQueryResource, httpClient, config, debounce)
+	if err != nil {
+		return nil, false, err
+	}
+	return stream.snapshot()
+}
+
+// stopStream stops the stream of an HTTPQueryResource that was deleted or no longer streams
+func (r *HTTPQueryResourceReconciler) stopStream(key types.NamespacedName) {
+	if r.streams != nil {
+		r.streams.stop(key)
+	}
+}
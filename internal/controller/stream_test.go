@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// streamingHTTPClient connects to a stream that sends the events written to its channel
+type streamingHTTPClient struct {
+	util.HTTPClient
+	events  chan util.StreamEvent
+	configs chan util.HTTPConfig
+}
+
+func (c *streamingHTTPClient) Stream(ctx context.Context, config util.HTTPConfig, onEvent func(util.StreamEvent)) error {
+	c.configs <- config
+	config.Stream.OnConnect()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e := <-c.events:
+			onEvent(e)
+		}
+	}
+}
+
+func TestStreamItems(t *testing.T) {
+	httpClient := &streamingHTTPClient{events: make(chan util.StreamEvent), configs: make(chan util.HTTPConfig, 10)}
+	r := &HTTPQueryResourceReconciler{Log: logr.Discard()}
+	httpQueryResource := &httpv1alpha1.HTTPQueryResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "team-a"},
+		Spec: httpv1alpha1.HTTPQueryResourceSpec{HTTP: httpv1alpha1.HTTPSpec{
+			URL: "https://api.example.com/users/changes",
+			Stream: &httpv1alpha1.HTTPStreamSpec{
+				Format: util.StreamFormatNDJSON, KeyField: "id", DataPath: "user", SnapshotTypes: []string{"synced"}, Debounce: "50ms",
+			},
+		}},
+	}
+	key := types.NamespacedName{Namespace: "team-a", Name: "users"}
+	config := util.HTTPConfig{URL: httpQueryResource.Spec.HTTP.URL}
+
+	_, _, err := r.streamItems(httpQueryResource, httpClient, config)
+	assert.ErrorIs(t, err, errStreamPending)
+	started := <-httpClient.configs
+	assert.Equal(t, util.StreamFormatNDJSON, started.Stream.Format)
+
+	triggered := func() {
+		select {
+		case e := <-r.streams.events:
+			assert.Equal(t, key.Name, e.Object.GetName())
+			assert.Equal(t, key.Namespace, e.Object.GetNamespace())
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream did not trigger a reconciliation")
+		}
+	}
+
+	// A connected stream stays pending until it sends events
+	select {
+	case <-r.streams.events:
+		t.Fatal("stream without events triggered a reconciliation")
+	case <-time.After(200 * time.Millisecond):
+	}
+	_, _, err = r.streamItems(httpQueryResource, httpClient, config)
+	assert.ErrorIs(t, err, errStreamPending)
+
+	httpClient.events <- util.StreamEvent{Data: []byte(`{"type": "upsert", "user": {"id": "1", "name": "alice"}}`)}
+	httpClient.events <- util.StreamEvent{Data: []byte(`{"type": "upsert", "user": {"id": "2", "name": "bob"}}`)}
+	httpClient.events <- util.StreamEvent{Data: []byte(`{"type": "delete", "user": {"id": "1"}}`)}
+
+	// The changes are debounced into a single reconciliation
+	triggered()
+	items, complete, err := r.streamItems(httpQueryResource, httpClient, config)
+	require.NoError(t, err)
+	assert.Equal(t, []util.ItemResult{{"id": "2", "name": "bob"}}, items)
+	assert.False(t, complete, "no snapshot event yet")
+
+	httpClient.events <- util.StreamEvent{Data: []byte(`{"type": "synced"}`)}
+	triggered()
+	items, complete, err = r.streamItems(httpQueryResource, httpClient, config)
+	require.NoError(t, err)
+	assert.Equal(t, []util.ItemResult{{"id": "2", "name": "bob"}}, items)
+	assert.True(t, complete)
+
+	t.Run("restarted when the configuration changes", func(t *testing.T) {
+		config.Headers = map[string]string{"X-Tenant": "b"}
+		_, _, err := r.streamItems(httpQueryResource, httpClient, config)
+		assert.ErrorIs(t, err, errStreamPending)
+		assert.Equal(t, "b", (<-httpClient.configs).Headers["X-Tenant"])
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		r.stopStream(key)
+		assert.Empty(t, r.streams.streams)
+	})
+}
+
+func TestReconcileResources_StreamPrune(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, httpv1alpha1.AddToScheme(scheme))
+
+	enabled := true
+	httpQueryResource := &httpv1alpha1.HTTPQueryResource{
+		ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "team-a", UID: "users-uid"},
+		Spec: httpv1alpha1.HTTPQueryResourceSpec{
+			HTTP: httpv1alpha1.HTTPSpec{
+				URL:    "https://api.example.com/users/changes",
+				Stream: &httpv1alpha1.HTTPStreamSpec{Format: util.StreamFormatNDJSON, KeyField: "id", SnapshotTypes: []string{"synced"}, Debounce: "10ms"},
+			},
+			Template: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: user-{{ .Item.id }}`,
+			Prune: &enabled,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "user-carol",
+		Namespace: "team-a",
+		Labels:    map[string]string{ManagedByLabel: ControllerName},
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: httpv1alpha1.GroupVersion.String(), Kind: "HTTPQueryResource", Name: "users", UID: "users-uid", Controller: &enabled,
+		}},
+	}}).Build()
+	httpClient := &streamingHTTPClient{events: make(chan util.StreamEvent), configs: make(chan util.HTTPConfig, 10)}
+	r := &HTTPQueryResourceReconciler{
+		Client:    fakeClient,
+		Scheme:    scheme,
+		Log:       logr.Discard(),
+		OwnedGVKs: []schema.GroupVersionKind{corev1.SchemeGroupVersion.WithKind("ConfigMap")},
+	}
+	defer r.stopStream(client.ObjectKeyFromObject(httpQueryResource))
+	reconcileAfterEvent := func(e util.StreamEvent) {
+		httpClient.events <- e
+		select {
+		case <-r.streams.events:
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream did not trigger a reconciliation")
+		}
+		_, err := r.reconcileResources(context.Background(), httpQueryResource, httpClient)
+		require.NoError(t, err)
+	}
+	exists := func(name string) bool {
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: "team-a", Name: name}, &corev1.ConfigMap{})
+		require.NoError(t, client.IgnoreNotFound(err))
+		return err == nil
+	}
+
+	_, err := r.reconcileResources(context.Background(), httpQueryResource, httpClient)
+	assert.ErrorIs(t, err, errStreamPending)
+	<-httpClient.configs
+
+	reconcileAfterEvent(util.StreamEvent{Data: []byte(`{"id": "alice"}`)})
+	assert.True(t, exists("user-alice"))
+	assert.True(t, exists("user-carol"), "not pruned before the snapshot event")
+
+	reconcileAfterEvent(util.StreamEvent{Data: []byte(`{"type": "synced"}`)})
+	assert.True(t, exists("user-alice"))
+	assert.False(t, exists("user-carol"), "pruned after the snapshot event")
+}
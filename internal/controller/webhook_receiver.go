@@ -0,0 +1,286 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+const (
+	// WebhookPathPrefix is the path prefix of the webhook listener, followed by <namespace>/<name>
+	WebhookPathPrefix = "/webhooks/"
+
+	WebhookTypeHMAC  = "hmac"
+	WebhookTypeToken = "token"
+
+	// DefaultWebhookTokenHeader carries the shared secret of token webhooks
+	DefaultWebhookTokenHeader = "Authorization"
+	// DefaultWebhookTimestampTolerance is the maximum age of a signed hmac webhook call
+	DefaultWebhookTimestampTolerance = 5 * time.Minute
+)
+
+// webhookPath returns the path of the webhook listener that triggers the HTTPQueryResource
+func webhookPath(key types.NamespacedName) string {
+	return WebhookPathPrefix + key.Namespace + "/" + key.Name
+}
+
+// webhookCall is a webhook call waiting to be reconciled
+type webhookCall struct {
+	received metav1.Time
+	// items of the payload, if the webhook uses the payload
+	items []util.ItemResult
+}
+
+// WebhookReceiver serves the webhook paths of HTTPQueryResources. An authenticated call enqueues the
+// HTTPQueryResource, optionally with its payload as the items of the poll response.
+type WebhookReceiver struct {
+	Client       client.Client
+	Log          logr.Logger
+	AuthResolver *util.AuthResolver
+	// MaxBodyBytes limits the size of a webhook body. Defaults to util.DefaultMaxResponseBytes.
+	MaxBodyBytes int64
+
+	events  chan event.GenericEvent
+	mu      sync.Mutex
+	pending map[types.NamespacedName]*webhookCall
+	// accepted holds the signatures of accepted hmac calls until their timestamp expires
+	accepted map[string]time.Time
+}
+
+// NewWebhookReceiver creates a webhook receiver. Its Events must be watched by the HTTPQueryResource controller.
+func NewWebhookReceiver(c client.Client, log logr.Logger) *WebhookReceiver {
+	return &WebhookReceiver{
+		Client:       c,
+		Log:          log,
+		AuthResolver: util.NewAuthResolver(c, log),
+		events:       make(chan event.GenericEvent),
+		pending:      map[types.NamespacedName]*webhookCall{},
+		accepted:     map[string]time.Time{},
+	}
+}
+
+// Events returns the channel of HTTPQueryResources triggered by webhook calls
+func (w *WebhookReceiver) Events() <-chan event.GenericEvent {
+	return w.events
+}
+
+// take returns and forgets the pending webhook call of an HTTPQueryResource, if any
+func (w *WebhookReceiver) take(key types.NamespacedName) (*webhookCall, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	call, ok := w.pending[key]
+	delete(w.pending, key)
+	return call, ok
+}
+
+// ServeHTTP handles POST /webhooks/<namespace>/<name>
+func (w *WebhookReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, WebhookPathPrefix), "/")
+	if !strings.HasPrefix(req.URL.Path, WebhookPathPrefix) || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		http.NotFound(rw, req)
+		return
+	}
+	if req.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	key := types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	log := w.Log.WithValues("httpqueryresource", key.String())
+	ctx := req.Context()
+
+	httpQueryResource := &httpv1alpha1.HTTPQueryResource{}
+	if err := w.Client.Get(ctx, key, httpQueryResource); err != nil {
+		if apierrors.IsNotFound(err) {
+			http.NotFound(rw, req)
+			return
+		}
+		log.Error(err, "Failed to get HTTPQueryResource for webhook call")
+		http.Error(rw, "internal error", http.StatusInternalServerError)
+		return
+	}
+	spec := httpQueryResource.Spec.Webhook
+	if spec == nil {
+		// Do not reveal resources without a webhook
+		http.NotFound(rw, req)
+		return
+	}
+
+	maxBytes := w.MaxBodyBytes
+	if maxBytes <= 0 {
+		maxBytes = util.DefaultMaxResponseBytes
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(rw, "request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(rw, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	secrets, err := w.AuthResolver.ResolveTemplateValues(ctx, key.Namespace, []httpv1alpha1.HTTPTemplateValue{
+		{Name: "secret", SecretKeyRef: &spec.SecretRef},
+	})
+	if err != nil {
+		log.Error(err, "Failed to resolve webhook secret")
+		http.Error(rw, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if err := w.verifyWebhook(key, spec, req.Header, body, secrets["secret"], time.Now()); err != nil {
+		log.Info("Rejected webhook call", "reason", err.Error())
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	call := &webhookCall{received: metav1.Now()}
+	if spec.UsePayload {
+		call.items, err = util.ParseItems(ctx, body, httpQueryResource.Spec.HTTP.ResponseFormat, req.Header.Get("Content-Type"), httpQueryResource.Spec.HTTP.Transform, httpQueryResource.Spec.HTTP.ResponsePath)
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.mu.Lock()
+	w.pending[key] = call
+	w.mu.Unlock()
+
+	select {
+	case w.events <- event.GenericEvent{Object: httpQueryResource}:
+	case <-ctx.Done():
+		return
+	case <-time.After(10 * time.Second):
+		// The call stays pending and is picked up by the next reconciliation
+		log.Info("Timed out enqueueing webhook call")
+	}
+
+	log.Info("Accepted webhook call", "items", len(call.items))
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// verifyWebhook authenticates a webhook call to the HTTPQueryResource with the shared secret.
+// Signed hmac calls must carry a timestamp within the tolerance, and are accepted only once.
+func (w *WebhookReceiver) verifyWebhook(key types.NamespacedName, spec *httpv1alpha1.HTTPWebhookSpec, header http.Header, body []byte, secret string, now time.Time) error {
+	if secret == "" {
+		return fmt.Errorf("webhook secret is empty")
+	}
+	switch spec.Type {
+	case "", WebhookTypeHMAC:
+		headerName := spec.Header
+		if headerName == "" {
+			headerName = util.DefaultHMACSignatureHeader
+		}
+		signature := header.Get(headerName)
+		if signature == "" {
+			return fmt.Errorf("missing %s header", headerName)
+		}
+		if spec.InsecureSkipTimestamp {
+			return util.VerifyHMACSignature(body, signature, secret, spec.SignaturePrefix, spec.Algorithm, spec.Encoding)
+		}
+
+		timestamp, expires, err := verifyWebhookTimestamp(spec, header, now)
+		if err != nil {
+			return err
+		}
+		signed := append([]byte(timestamp+"."), body...)
+		if err := util.VerifyHMACSignature(signed, signature, secret, spec.SignaturePrefix, spec.Algorithm, spec.Encoding); err != nil {
+			return err
+		}
+		return w.acceptOnce(key.String()+"/"+signature, expires, now)
+	case WebhookTypeToken:
+		headerName := spec.Header
+		if headerName == "" {
+			headerName = DefaultWebhookTokenHeader
+		}
+		token := header.Get(headerName)
+		if strings.EqualFold(headerName, "Authorization") && len(token) > 7 && strings.EqualFold(token[:7], "Bearer ") {
+			token = token[7:]
+		}
+		if token == "" {
+			return fmt.Errorf("missing %s header", headerName)
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			return fmt.Errorf("token mismatch")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported webhook type: %s", spec.Type)
+	}
+}
+
+// verifyWebhookTimestamp checks that the timestamp of a call is within the tolerance, and returns
+// the timestamp and the time the call expires
+func verifyWebhookTimestamp(spec *httpv1alpha1.HTTPWebhookSpec, header http.Header, now time.Time) (string, time.Time, error) {
+	tolerance, err := parseDuration("webhook.timestampTolerance", spec.TimestampTolerance)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if tolerance == 0 {
+		tolerance = DefaultWebhookTimestampTolerance
+	}
+	headerName := spec.TimestampHeader
+	if headerName == "" {
+		headerName = util.DefaultHMACTimestampHeader
+	}
+	value := header.Get(headerName)
+	if value == "" {
+		return "", time.Time{}, fmt.Errorf("missing %s header", headerName)
+	}
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid %s header '%s'", headerName, value)
+	}
+	timestamp := time.Unix(seconds, 0)
+	if timestamp.Before(now.Add(-tolerance)) || timestamp.After(now.Add(tolerance)) {
+		return "", time.Time{}, fmt.Errorf("timestamp %s is outside the tolerance of %s", timestamp.UTC().Format(time.RFC3339), tolerance)
+	}
+	return value, timestamp.Add(tolerance), nil
+}
+
+// acceptOnce records an accepted signature until it expires, and rejects signatures accepted before
+func (w *WebhookReceiver) acceptOnce(signature string, expires, now time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for accepted, acceptedExpires := range w.accepted {
+		if !now.Before(acceptedExpires) {
+			delete(w.accepted, accepted)
+		}
+	}
+	if _, ok := w.accepted[signature]; ok {
+		return fmt.Errorf("signature was already used")
+	}
+	w.accepted[signature] = expires
+	return nil
+}
+
+// takeWebhookCall returns the pending webhook call of the HTTPQueryResource, if any, and records it in the status
+func (r *HTTPQueryResourceReconciler) takeWebhookCall(httpQueryResource *httpv1alpha1.HTTPQueryResource) *webhookCall {
+	if r.Webhooks == nil || httpQueryResource.Spec.Webhook == nil {
+		return nil
+	}
+	call, ok := r.Webhooks.take(client.ObjectKeyFromObject(httpQueryResource))
+	if !ok {
+		return nil
+	}
+	httpQueryResource.Status.LastWebhookTime = &call.received
+	return call
+}
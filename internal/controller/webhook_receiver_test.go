@@ -0,0 +1,163 @@
+package controller
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+func TestWebhookReceiver_ServeHTTP(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, httpv1alpha1.AddToScheme(scheme))
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-secret", Namespace: "team-a"},
+		Data:       map[string][]byte{"secret": []byte("s3cr3t")},
+	}
+	withWebhook := func(name string, webhook *httpv1alpha1.HTTPWebhookSpec) *httpv1alpha1.HTTPQueryResource {
+		return &httpv1alpha1.HTTPQueryResource{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"},
+			Spec: httpv1alpha1.HTTPQueryResourceSpec{
+				HTTP:    httpv1alpha1.HTTPSpec{URL: "https://api.example.com/users", ResponsePath: "users"},
+				Webhook: webhook,
+			},
+		}
+	}
+	secretRef := httpv1alpha1.HTTPKeyRef{Name: "webhook-secret", Key: "secret"}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(secret,
+			withWebhook("signed", &httpv1alpha1.HTTPWebhookSpec{SecretRef: secretRef, SignaturePrefix: "sha256="}),
+			withWebhook("github", &httpv1alpha1.HTTPWebhookSpec{SecretRef: secretRef, Header: "X-Hub-Signature-256", SignaturePrefix: "sha256=", InsecureSkipTimestamp: true}),
+			withWebhook("payload", &httpv1alpha1.HTTPWebhookSpec{SecretRef: secretRef, Type: WebhookTypeToken, UsePayload: true}),
+			withWebhook("polled", nil)).
+		Build()
+
+	receiver := NewWebhookReceiver(fakeClient, logr.Discard())
+	triggered := make(chan types.NamespacedName, 10)
+	go func() {
+		for e := range receiver.Events() {
+			triggered <- types.NamespacedName{Namespace: e.Object.GetNamespace(), Name: e.Object.GetName()}
+		}
+	}()
+
+	call := func(method, path, body string, headers map[string]string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	sign := func(message string) string {
+		mac := hmac.New(sha256.New, []byte("s3cr3t"))
+		mac.Write([]byte(message))
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+	signed := func(body string, timestamp time.Time) map[string]string {
+		unix := strconv.FormatInt(timestamp.Unix(), 10)
+		return map[string]string{"X-Timestamp": unix, "X-Signature": sign(unix + "." + body)}
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		body := `{"event": "user.created"}`
+		assert.Equal(t, http.StatusAccepted, call("POST", "/webhooks/team-a/signed", body, signed(body, time.Now())))
+		assert.Equal(t, types.NamespacedName{Namespace: "team-a", Name: "signed"}, <-triggered)
+
+		pending, ok := receiver.take(types.NamespacedName{Namespace: "team-a", Name: "signed"})
+		require.True(t, ok)
+		assert.Nil(t, pending.items)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		headers := signed(`{"other": true}`, time.Now())
+		assert.Equal(t, http.StatusUnauthorized, call("POST", "/webhooks/team-a/signed", `{}`, headers))
+		assert.Equal(t, http.StatusUnauthorized, call("POST", "/webhooks/team-a/signed", `{}`, map[string]string{"X-Signature": sign(`{}`)}), "body signed without timestamp")
+		assert.Equal(t, http.StatusUnauthorized, call("POST", "/webhooks/team-a/signed", `{}`, nil))
+	})
+
+	t.Run("replayed signature", func(t *testing.T) {
+		body := `{"event": "user.deleted"}`
+		headers := signed(body, time.Now().Add(-time.Minute))
+		assert.Equal(t, http.StatusAccepted, call("POST", "/webhooks/team-a/signed", body, headers))
+		assert.Equal(t, types.NamespacedName{Namespace: "team-a", Name: "signed"}, <-triggered)
+		_, _ = receiver.take(types.NamespacedName{Namespace: "team-a", Name: "signed"})
+
+		assert.Equal(t, http.StatusUnauthorized, call("POST", "/webhooks/team-a/signed", body, headers), "same call again")
+		replayed := signed(body, time.Now())
+		replayed["X-Signature"] = headers["X-Signature"]
+		assert.Equal(t, http.StatusUnauthorized, call("POST", "/webhooks/team-a/signed", body, replayed), "signature with a new timestamp")
+	})
+
+	t.Run("expired signature", func(t *testing.T) {
+		body := `{"event": "user.updated"}`
+		assert.Equal(t, http.StatusUnauthorized, call("POST", "/webhooks/team-a/signed", body, signed(body, time.Now().Add(-10*time.Minute))))
+		assert.Equal(t, http.StatusUnauthorized, call("POST", "/webhooks/team-a/signed", body, signed(body, time.Now().Add(10*time.Minute))), "timestamp in the future")
+	})
+
+	t.Run("signature without timestamp", func(t *testing.T) {
+		body := `{"action": "push"}`
+		assert.Equal(t, http.StatusAccepted, call("POST", "/webhooks/team-a/github", body, map[string]string{"X-Hub-Signature-256": sign(body)}))
+		assert.Equal(t, types.NamespacedName{Namespace: "team-a", Name: "github"}, <-triggered)
+	})
+
+	t.Run("payload", func(t *testing.T) {
+		body := `{"users": [{"id": 1}, {"id": 2}]}`
+		assert.Equal(t, http.StatusAccepted, call("POST", "/webhooks/team-a/payload", body, map[string]string{"Authorization": "Bearer s3cr3t"}))
+		assert.Equal(t, types.NamespacedName{Namespace: "team-a", Name: "payload"}, <-triggered)
+
+		pending, ok := receiver.take(types.NamespacedName{Namespace: "team-a", Name: "payload"})
+		require.True(t, ok)
+		assert.Equal(t, []util.ItemResult{{"id": float64(1)}, {"id": float64(2)}}, pending.items)
+
+		assert.Equal(t, http.StatusBadRequest, call("POST", "/webhooks/team-a/payload", `{"groups": []}`, map[string]string{"Authorization": "s3cr3t"}))
+		assert.Equal(t, http.StatusUnauthorized, call("POST", "/webhooks/team-a/payload", body, map[string]string{"Authorization": "Bearer wrong"}))
+	})
+
+	t.Run("unknown paths", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, call("POST", "/webhooks/team-a/polled", `{}`, nil))
+		assert.Equal(t, http.StatusNotFound, call("POST", "/webhooks/team-a/missing", `{}`, nil))
+		assert.Equal(t, http.StatusNotFound, call("POST", "/webhooks/team-a", `{}`, nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, call("GET", "/webhooks/team-a/signed", "", nil))
+	})
+
+	assert.Empty(t, triggered)
+}
+
+func TestTakeWebhookCall(t *testing.T) {
+	receiver := NewWebhookReceiver(nil, logr.Discard())
+	key := types.NamespacedName{Namespace: "team-a", Name: "users"}
+	received := metav1.Now()
+	receiver.pending[key] = &webhookCall{received: received, items: []util.ItemResult{{"id": "1"}}}
+
+	httpQueryResource := &httpv1alpha1.HTTPQueryResource{ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "team-a"}}
+	r := &HTTPQueryResourceReconciler{Webhooks: receiver}
+	assert.Nil(t, r.takeWebhookCall(httpQueryResource), "webhook not configured")
+
+	httpQueryResource.Spec.Webhook = &httpv1alpha1.HTTPWebhookSpec{}
+	call := r.takeWebhookCall(httpQueryResource)
+	require.NotNil(t, call)
+	assert.Len(t, call.items, 1)
+	assert.Equal(t, &received, httpQueryResource.Status.LastWebhookTime)
+	assert.Nil(t, r.takeWebhookCall(httpQueryResource), "calls are taken once")
+}
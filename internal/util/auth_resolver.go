@@ -2,12 +2,17 @@ package util
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
@@ -23,6 +28,11 @@ type ResolvedAuthConfig struct {
 type AuthResolver struct {
 	Client client.Client
 	Log    logr.Logger
+
+	// saTokens caches minted ServiceAccount tokens
+	saTokens serviceAccountTokenCache
+	// now is overridden in tests
+	now func() time.Time
 }
 
 // NewAuthResolver creates a new AuthResolver
@@ -37,6 +47,11 @@ func NewAuthResolver(client client.Client, log logr.Logger) *AuthResolver {
 func (ar *AuthResolver) ResolveAuthenticationConfig(ctx context.Context, namespace string, authRef *httpv1alpha1.HTTPAuthenticationRef) (*ResolvedAuthConfig, error) {
 	log := ar.Log.WithValues("authRef", authRef.Name)
 
+	// ServiceAccount tokens are minted instead of read from a Secret
+	if authRef.Type == "serviceAccountToken" {
+		return ar.resolveServiceAccountToken(ctx, namespace, authRef)
+	}
+
 	// Determine secret namespace (default to provided namespace)
 	secretNamespace := authRef.Namespace
 	if secretNamespace == "" {
@@ -111,16 +126,89 @@ func (ar *AuthResolver) ResolveAuthenticationConfig(ctx context.Context, namespa
 		clientSecret := getValue(authRef.ClientSecretKey, "clientSecret")
 		tokenURL := authRef.TokenURL
 		scopes := authRef.Scopes
+		grantType := defaultString(authRef.GrantType, OAuth2GrantClientCredentials)
 
 		authConfig.AuthConfig["clientId"] = clientID
 		authConfig.AuthConfig["clientSecret"] = clientSecret
 		authConfig.AuthConfig["tokenUrl"] = tokenURL
 		authConfig.AuthConfig["scopes"] = scopes
+		authConfig.AuthConfig["grantType"] = grantType
+		authConfig.AuthConfig["clientAuthentication"] = authRef.ClientAuthentication
+		authConfig.AuthConfig["audience"] = authRef.Audience
+		authConfig.AuthConfig["secretRef"] = secretKey.String()
+		for name, value := range authRef.TokenParams {
+			authConfig.AuthConfig[OAuth2ExtraParamPrefix+name] = value
+		}
 
-		if clientID == "" || clientSecret == "" || tokenURL == "" {
+		if tokenURL == "" {
+			return nil, fmt.Errorf("OAuth2 authentication requires tokenUrl in spec")
+		}
+		if grantType == OAuth2GrantJWTBearer || authRef.ClientAuthentication == privateKeyJWTClientAuth {
+			privateKey := getValue(authRef.PrivateKeyKey, "privateKey")
+			if clientID == "" || privateKey == "" {
+				return nil, fmt.Errorf("OAuth2 JWT assertions require clientId and privateKey in secret")
+			}
+			authConfig.AuthConfig["privateKey"] = privateKey
+			authConfig.AuthConfig["keyId"] = authRef.KeyID
+			authConfig.AuthConfig["subject"] = authRef.Subject
+		} else if grantType == OAuth2GrantClientCredentials && (clientID == "" || clientSecret == "") {
 			return nil, fmt.Errorf("OAuth2 authentication requires clientId, clientSecret in secret and tokenUrl in spec")
 		}
 
+		switch grantType {
+		case OAuth2GrantRefreshToken:
+			refreshTokenKey := defaultString(authRef.RefreshTokenKey, "refreshToken")
+			refreshToken := getValue(refreshTokenKey, "")
+			if refreshToken == "" {
+				return nil, fmt.Errorf("OAuth2 refresh_token grant requires a refresh token in secret key '%s'", refreshTokenKey)
+			}
+			authConfig.AuthConfig["refreshToken"] = refreshToken
+			if authRef.WriteBackRefreshToken {
+				authConfig.AuthConfig["refreshTokenKey"] = refreshTokenKey
+			}
+		case OAuth2GrantTokenExchange:
+			subjectToken := getValue(authRef.SubjectTokenKey, "subjectToken")
+			if subjectToken == "" {
+				return nil, fmt.Errorf("OAuth2 token_exchange grant requires a subject token in secret")
+			}
+			authConfig.AuthConfig["subjectToken"] = subjectToken
+			authConfig.AuthConfig["subjectTokenType"] = authRef.SubjectTokenType
+		}
+
+	case "awsSigV4":
+		accessKeyID := getValue(authRef.AccessKeyIDKey, "accessKeyId")
+		secretAccessKey := getValue(authRef.SecretAccessKeyKey, "secretAccessKey")
+
+		authConfig.AuthConfig["accessKeyId"] = accessKeyID
+		authConfig.AuthConfig["secretAccessKey"] = secretAccessKey
+		authConfig.AuthConfig["sessionToken"] = getValue(authRef.SessionTokenKey, "sessionToken")
+		authConfig.AuthConfig["region"] = authRef.Region
+		authConfig.AuthConfig["service"] = authRef.Service
+
+		if accessKeyID == "" || secretAccessKey == "" || authRef.Region == "" || authRef.Service == "" {
+			return nil, fmt.Errorf("AWS SigV4 authentication requires accessKeyId, secretAccessKey in secret and region, service in spec")
+		}
+
+	case "hmac":
+		hmacSpec := authRef.HMAC
+		if hmacSpec == nil {
+			hmacSpec = &httpv1alpha1.HTTPHMACSpec{}
+		}
+		secretValue := getValue(hmacSpec.SecretKey, "secret")
+
+		authConfig.AuthConfig["secret"] = secretValue
+		authConfig.AuthConfig["algorithm"] = hmacSpec.Algorithm
+		authConfig.AuthConfig["encoding"] = hmacSpec.Encoding
+		authConfig.AuthConfig["canonicalTemplate"] = hmacSpec.CanonicalTemplate
+		authConfig.AuthConfig["signatureHeader"] = hmacSpec.SignatureHeader
+		authConfig.AuthConfig["signaturePrefix"] = hmacSpec.SignaturePrefix
+		authConfig.AuthConfig["timestampHeader"] = hmacSpec.TimestampHeader
+		authConfig.AuthConfig["timestampFormat"] = hmacSpec.TimestampFormat
+
+		if secretValue == "" {
+			return nil, fmt.Errorf("HMAC authentication requires a shared secret in secret")
+		}
+
 	default:
 		return nil, fmt.Errorf("unsupported authentication type: %s", authRef.Type)
 	}
@@ -128,3 +216,71 @@ func (ar *AuthResolver) ResolveAuthenticationConfig(ctx context.Context, namespa
 	log.V(1).Info("Successfully resolved authentication configuration", "type", authRef.Type, "secret", authRef.Name)
 	return authConfig, nil
 }
+
+// RefreshTokenAnnotation marks the Secret key a rotated OAuth2 refresh token was written back to,
+// as "<key>:<sha256 of the token>", so that the write-back is not mistaken for a credential change.
+const RefreshTokenAnnotation = "konnektr.io/refresh-token-write-back"
+
+// refreshTokenAnnotationValue returns the RefreshTokenAnnotation value for a token written to key
+func refreshTokenAnnotationValue(key, refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return key + ":" + hex.EncodeToString(hash[:])
+}
+
+// WrittenBackRefreshTokenKey returns the key of the Secret holding a refresh token written back by
+// the operator, if the key still holds the token that was written back.
+func WrittenBackRefreshTokenKey(secret *corev1.Secret) (string, bool) {
+	annotation, ok := secret.Annotations[RefreshTokenAnnotation]
+	if !ok {
+		return "", false
+	}
+	key, _, _ := strings.Cut(annotation, ":")
+	value, ok := secret.Data[key]
+	if !ok || annotation != refreshTokenAnnotationValue(key, string(value)) {
+		return "", false
+	}
+	return key, true
+}
+
+// RefreshTokenWriter returns a callback writing rotated OAuth2 refresh tokens back to the Secret
+// they were read from, or nil if write-back is not configured. The write-back is recorded in the
+// RefreshTokenAnnotation of the Secret.
+func (ar *AuthResolver) RefreshTokenWriter(resolved *ResolvedAuthConfig) func(string) {
+	if resolved == nil || resolved.AuthConfig["refreshTokenKey"] == "" {
+		return nil
+	}
+	namespace, name, _ := strings.Cut(resolved.AuthConfig["secretRef"], "/")
+	secretKey := types.NamespacedName{Namespace: namespace, Name: name}
+	key := resolved.AuthConfig["refreshTokenKey"]
+	log := ar.Log.WithValues("secret", secretKey.String())
+
+	return func(refreshToken string) {
+		// The token source may outlive the reconcile that created it
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			secret := &corev1.Secret{}
+			if err := ar.Client.Get(ctx, secretKey, secret); err != nil {
+				return err
+			}
+			if string(secret.Data[key]) == refreshToken {
+				return nil
+			}
+			if secret.Data == nil {
+				secret.Data = map[string][]byte{}
+			}
+			secret.Data[key] = []byte(refreshToken)
+			if secret.Annotations == nil {
+				secret.Annotations = map[string]string{}
+			}
+			secret.Annotations[RefreshTokenAnnotation] = refreshTokenAnnotationValue(key, refreshToken)
+			return ar.Client.Update(ctx, secret)
+		})
+		if err != nil {
+			log.Error(err, "Failed to write rotated OAuth2 refresh token back to secret")
+			return
+		}
+		log.V(1).Info("Wrote rotated OAuth2 refresh token back to secret")
+	}
+}
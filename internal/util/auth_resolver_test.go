@@ -179,10 +179,164 @@ func TestAuthResolver_ResolveAuthenticationConfig(t *testing.T) {
 			expected: &ResolvedAuthConfig{
 				AuthType: "oauth2",
 				AuthConfig: map[string]string{
-					"clientId":     "client123",
-					"clientSecret": "secret456",
-					"tokenUrl":     "https://auth.example.com/token",
-					"scopes":       "read write",
+					"clientId":             "client123",
+					"clientSecret":         "secret456",
+					"tokenUrl":             "https://auth.example.com/token",
+					"scopes":               "read write",
+					"grantType":            "client_credentials",
+					"clientAuthentication": "",
+					"audience":             "",
+					"secretRef":            "default/test-secret",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "oauth2 refresh token grant",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-secret",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"clientId":     []byte("client123"),
+					"refreshToken": []byte("refresh789"),
+				},
+			},
+			authRef: &httpv1alpha1.HTTPAuthenticationRef{
+				Name:                  "test-secret",
+				Type:                  "oauth2",
+				TokenURL:              "https://auth.example.com/token",
+				GrantType:             "refresh_token",
+				WriteBackRefreshToken: true,
+				TokenParams:           map[string]string{"resource": "https://api.example.com"},
+			},
+			namespace: "default",
+			expected: &ResolvedAuthConfig{
+				AuthType: "oauth2",
+				AuthConfig: map[string]string{
+					"clientId":             "client123",
+					"clientSecret":         "",
+					"tokenUrl":             "https://auth.example.com/token",
+					"scopes":               "",
+					"grantType":            "refresh_token",
+					"clientAuthentication": "",
+					"audience":             "",
+					"secretRef":            "default/test-secret",
+					"param.resource":       "https://api.example.com",
+					"refreshToken":         "refresh789",
+					"refreshTokenKey":      "refreshToken",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "oauth2 jwt bearer grant missing private key",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-secret",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"clientId": []byte("client123"),
+				},
+			},
+			authRef: &httpv1alpha1.HTTPAuthenticationRef{
+				Name:      "test-secret",
+				Type:      "oauth2",
+				TokenURL:  "https://auth.example.com/token",
+				GrantType: "jwt_bearer",
+			},
+			namespace: "default",
+			expected:  nil,
+			wantErr:   true,
+		},
+		{
+			name: "aws sigv4 auth",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-secret",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"AWS_ACCESS_KEY_ID":     []byte("AKIDEXAMPLE"),
+					"AWS_SECRET_ACCESS_KEY": []byte("secret456"),
+				},
+			},
+			authRef: &httpv1alpha1.HTTPAuthenticationRef{
+				Name:               "test-secret",
+				Type:               "awsSigV4",
+				AccessKeyIDKey:     "AWS_ACCESS_KEY_ID",
+				SecretAccessKeyKey: "AWS_SECRET_ACCESS_KEY",
+				Region:             "eu-west-1",
+				Service:            "execute-api",
+			},
+			namespace: "default",
+			expected: &ResolvedAuthConfig{
+				AuthType: "awsSigV4",
+				AuthConfig: map[string]string{
+					"accessKeyId":     "AKIDEXAMPLE",
+					"secretAccessKey": "secret456",
+					"sessionToken":    "",
+					"region":          "eu-west-1",
+					"service":         "execute-api",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "aws sigv4 missing region",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-secret",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"accessKeyId":     []byte("AKIDEXAMPLE"),
+					"secretAccessKey": []byte("secret456"),
+				},
+			},
+			authRef: &httpv1alpha1.HTTPAuthenticationRef{
+				Name:    "test-secret",
+				Type:    "awsSigV4",
+				Service: "s3",
+			},
+			namespace: "default",
+			expected:  nil,
+			wantErr:   true,
+		},
+		{
+			name: "hmac auth",
+			secret: &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-secret",
+					Namespace: "default",
+				},
+				Data: map[string][]byte{
+					"signingKey": []byte("s3cr3t"),
+				},
+			},
+			authRef: &httpv1alpha1.HTTPAuthenticationRef{
+				Name: "test-secret",
+				Type: "hmac",
+				HMAC: &httpv1alpha1.HTTPHMACSpec{
+					SecretKey:       "signingKey",
+					Algorithm:       "sha512",
+					SignatureHeader: "X-Partner-Signature",
+				},
+			},
+			namespace: "default",
+			expected: &ResolvedAuthConfig{
+				AuthType: "hmac",
+				AuthConfig: map[string]string{
+					"secret":            "s3cr3t",
+					"algorithm":         "sha512",
+					"encoding":          "",
+					"canonicalTemplate": "",
+					"signatureHeader":   "X-Partner-Signature",
+					"signaturePrefix":   "",
+					"timestampHeader":   "",
+					"timestampFormat":   "",
 				},
 			},
 			wantErr: false,
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+// filterCostLimit bounds the evaluation cost of a filter for a single item
+const filterCostLimit = 1000000
+
+// ItemFilter is a compiled CEL expression that selects items. The item is exposed as `item`.
+type ItemFilter struct {
+	program cel.Program
+}
+
+// CompileItemFilter compiles a CEL filter expression, which must evaluate to a bool,
+// e.g. `item.status == "active" && item.region in ["eu"]`.
+func CompileItemFilter(expression string) (*ItemFilter, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("item", cel.MapType(cel.StringType, cel.DynType)),
+		ext.Strings(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid CEL expression: %w", issues.Err())
+	}
+	if outputType := ast.OutputType(); outputType != cel.BoolType && outputType != cel.DynType {
+		return nil, fmt.Errorf("invalid CEL expression: must evaluate to bool, not %s", outputType)
+	}
+	program, err := env.Program(ast, cel.CostLimit(filterCostLimit))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CEL expression: %w", err)
+	}
+	return &ItemFilter{program: program}, nil
+}
+
+// Matches evaluates the filter for an item
+func (f *ItemFilter) Matches(item ItemResult) (bool, error) {
+	out, _, err := f.program.Eval(map[string]interface{}{"item": map[string]interface{}(item)})
+	if err != nil {
+		return false, err
+	}
+	matches, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("filter evaluated to %s, not bool", out.Type().TypeName())
+	}
+	return matches, nil
+}
+
+// Filter returns the items matching the filter. An item the filter fails for fails the whole
+// filter, so that it is not pruned by mistake.
+func (f *ItemFilter) Filter(items []ItemResult) ([]ItemResult, error) {
+	filtered := make([]ItemResult, 0, len(items))
+	for i, item := range items {
+		matches, err := f.Matches(item)
+		if err != nil {
+			return nil, ItemError{Index: i, Err: fmt.Errorf("filter failed: %w", err)}
+		}
+		if matches {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,66 @@
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileItemFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{name: "comparison", expression: `item.status == "active" && item.region in ["eu", "us"]`},
+		{name: "string function", expression: `item.name.lowerAscii().startsWith("a")`},
+		{name: "presence test", expression: `has(item.owner)`},
+		{name: "syntax error", expression: `item.status ==`, wantErr: "Syntax error"},
+		{name: "undeclared variable", expression: `user.status == "active"`, wantErr: "undeclared reference to 'user'"},
+		{name: "not a bool", expression: `size(item)`, wantErr: "must evaluate to bool, not int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileItemFilter(tt.expression)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestItemFilter_Filter(t *testing.T) {
+	items := []ItemResult{
+		{"id": "a", "status": "active", "region": "eu"},
+		{"id": "b", "status": "inactive", "region": "eu"},
+		{"id": "c", "status": "active", "region": "ap"},
+	}
+
+	filter, err := CompileItemFilter(`item.status == "active" && item.region in ["eu"]`)
+	require.NoError(t, err)
+	filtered, err := filter.Filter(items)
+	require.NoError(t, err)
+	assert.Equal(t, []ItemResult{items[0]}, filtered)
+
+	t.Run("missing field", func(t *testing.T) {
+		filter, err := CompileItemFilter(`item.owner == "alice"`)
+		require.NoError(t, err)
+		_, err = filter.Filter(items)
+		var itemErr ItemError
+		require.True(t, errors.As(err, &itemErr))
+		assert.Equal(t, 0, itemErr.Index)
+		assert.Contains(t, err.Error(), "no such key: owner")
+	})
+
+	t.Run("dynamic result that is not a bool", func(t *testing.T) {
+		filter, err := CompileItemFilter(`item.status`)
+		require.NoError(t, err)
+		_, err = filter.Filter(items)
+		assert.ErrorContains(t, err, "item 0: filter failed: filter evaluated to string, not bool")
+	})
+}
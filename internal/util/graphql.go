@@ -0,0 +1,143 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// DefaultGraphQLCursorVariable is the variable receiving the cursor of the next page
+const DefaultGraphQLCursorVariable = "after"
+
+// graphQLRequest is the JSON body of a GraphQL request
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// GraphQLError is returned when a GraphQL response contains errors.
+// GraphQL servers report errors with a successful HTTP status.
+type GraphQLError struct {
+	Messages []string
+}
+
+// Error implements the error interface
+func (e *GraphQLError) Error() string {
+	return fmt.Sprintf("GraphQL query failed: %s", strings.Join(e.Messages, "; "))
+}
+
+// executeGraphQL sends the GraphQL query of the config and, with pagination, follows the
+// connection while pageInfo.hasNextPage is true. The body of every page is written to bodies.
+func (r *RESTClient) executeGraphQL(ctx context.Context, config HTTPConfig, bodies io.Writer) ([]ItemResult, error) {
+	if config.Body != "" {
+		return nil, fmt.Errorf("a request body cannot be combined with a GraphQL query")
+	}
+	if config.Pagination != nil {
+		return nil, fmt.Errorf("pagination cannot be combined with a GraphQL query, use GraphQL pagination instead")
+	}
+
+	gql := config.GraphQL
+	variables := maps.Clone(gql.Variables)
+	if variables == nil {
+		variables = make(map[string]interface{})
+	}
+
+	p := gql.Pagination
+	if p == nil {
+		body, err := r.fetchGraphQL(ctx, config, variables)
+		if errors.Is(err, errEmptyResponse) {
+			return []ItemResult{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		bodies.Write(body)
+		return r.extractItems(ctx, body, config)
+	}
+
+	if p.ConnectionPath == "" {
+		return nil, fmt.Errorf("GraphQL pagination requires connectionPath")
+	}
+	maxPages := p.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	cursorVariable := defaultString(p.CursorVariable, DefaultGraphQLCursorVariable)
+
+	allItems := []ItemResult{}
+	for fetched := 1; ; fetched++ {
+		body, err := r.fetchGraphQL(ctx, config, variables)
+		if errors.Is(err, errEmptyResponse) {
+			return allItems, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", fetched, err)
+		}
+
+		bodies.Write(body)
+
+		items, err := r.extractItems(ctx, body, config)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", fetched, err)
+		}
+		allItems = append(allItems, items...)
+
+		pageInfo := gjson.GetBytes(body, p.ConnectionPath+".pageInfo")
+		if !pageInfo.IsObject() {
+			return nil, fmt.Errorf("page %d: pageInfo not found at '%s'", fetched, p.ConnectionPath)
+		}
+		if !pageInfo.Get("hasNextPage").Bool() {
+			return allItems, nil
+		}
+		endCursor := pageInfo.Get("endCursor").String()
+		if endCursor == "" || endCursor == variables[cursorVariable] {
+			return nil, fmt.Errorf("page %d: hasNextPage is true but endCursor did not advance", fetched)
+		}
+		if fetched >= maxPages {
+			return nil, fmt.Errorf("pagination exceeded the maximum of %d pages", maxPages)
+		}
+		variables[cursorVariable] = endCursor
+	}
+}
+
+// fetchGraphQL sends the GraphQL query with the given variables and returns the response body.
+// A response with a non-empty errors array is returned as a GraphQLError.
+func (r *RESTClient) fetchGraphQL(ctx context.Context, config HTTPConfig, variables map[string]interface{}) ([]byte, error) {
+	requestBody, err := json.Marshal(graphQLRequest{
+		Query:         config.GraphQL.Query,
+		Variables:     variables,
+		OperationName: config.GraphQL.OperationName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal GraphQL request: %w", err)
+	}
+
+	config.Method = "POST"
+	config.Body = string(requestBody)
+	config.ResponseFormat = ResponseFormatJSON
+
+	body, _, err := r.fetch(ctx, config, config.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := gjson.GetBytes(body, "errors"); errs.IsArray() && len(errs.Array()) > 0 {
+		gqlErr := &GraphQLError{}
+		for _, e := range errs.Array() {
+			message := e.Get("message").String()
+			if path := e.Get("path"); path.Exists() {
+				message = fmt.Sprintf("%s (path: %s)", message, path.Raw)
+			}
+			gqlErr.Messages = append(gqlErr.Messages, message)
+		}
+		return nil, gqlErr
+	}
+	return body, nil
+}
@@ -0,0 +1,105 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTClient_Execute_GraphQL(t *testing.T) {
+	client := NewRESTClient()
+
+	t.Run("sends query, variables and operation name", func(t *testing.T) {
+		var request graphQLRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "POST", r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			w.Write([]byte(`{"data": {"users": [{"id": "1"}, {"id": "2"}]}}`))
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:          server.URL,
+			Method:       "GET",
+			ResponsePath: "data.users",
+			GraphQL: &GraphQLConfig{
+				Query:         "query Users($team: String!) { users(team: $team) { id } }",
+				Variables:     map[string]interface{}{"team": "platform"},
+				OperationName: "Users",
+			},
+		})
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.Equal(t, "Users", request.OperationName)
+		assert.Equal(t, map[string]interface{}{"team": "platform"}, request.Variables)
+	})
+
+	t.Run("errors array fails the request", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": null, "errors": [{"message": "field 'foo' not found", "path": ["users", 0]}]}`))
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:     server.URL,
+			GraphQL: &GraphQLConfig{Query: "{ users { foo } }"},
+		})
+		assert.Nil(t, items)
+		var gqlErr *GraphQLError
+		require.ErrorAs(t, err, &gqlErr)
+		assert.Equal(t, []string{`field 'foo' not found (path: ["users", 0])`}, gqlErr.Messages)
+	})
+
+	t.Run("connection pagination", func(t *testing.T) {
+		var cursors []interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request graphQLRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			cursors = append(cursors, request.Variables["cursor"])
+			page := len(cursors)
+			fmt.Fprintf(w, `{"data": {"users": {"nodes": [{"id": %d}], "pageInfo": {"hasNextPage": %t, "endCursor": "c%d"}}}}`, page, page < 3, page)
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:          server.URL,
+			ResponsePath: "data.users.nodes",
+			GraphQL: &GraphQLConfig{
+				Query:     "query($cursor: String) { users(first: 1, after: $cursor) { nodes { id } pageInfo { hasNextPage endCursor } } }",
+				Variables: map[string]interface{}{"first": 1},
+				Pagination: &GraphQLPaginationConfig{
+					ConnectionPath: "data.users",
+					CursorVariable: "cursor",
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.Equal(t, []interface{}{nil, "c1", "c2"}, cursors)
+	})
+
+	t.Run("cursor that does not advance fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"data": {"users": {"nodes": [], "pageInfo": {"hasNextPage": true, "endCursor": "same"}}}}`))
+		}))
+		defer server.Close()
+
+		_, err := client.Execute(context.Background(), HTTPConfig{
+			URL:          server.URL,
+			ResponsePath: "data.users.nodes",
+			GraphQL: &GraphQLConfig{
+				Query:      "{ users { nodes { id } } }",
+				Pagination: &GraphQLPaginationConfig{ConnectionPath: "data.users"},
+			},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "endCursor did not advance")
+	})
+}
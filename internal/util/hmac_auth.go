@@ -0,0 +1,170 @@
+package util
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// Defaults of the HMAC signing configuration
+const (
+	DefaultHMACCanonicalTemplate = "{{ .Method }}\n{{ .Path }}\n{{ .Timestamp }}\n{{ .Body }}"
+	DefaultHMACSignatureHeader   = "X-Signature"
+	DefaultHMACTimestampHeader   = "X-Timestamp"
+)
+
+// hmacCanonicalData is the context of the canonical string template
+type hmacCanonicalData struct {
+	Method    string
+	Host      string
+	Path      string
+	Query     string
+	Timestamp string
+	Body      string
+}
+
+// signHMAC signs the request with an HMAC over the canonical string rendered from the request.
+// The timestamp header is set before signing, and the signature is sent in the signature header.
+// Supported authConfig keys: secret, algorithm (sha256, sha512, sha1), encoding (hex, base64),
+// canonicalTemplate, signatureHeader, signaturePrefix, timestampHeader and
+// timestampFormat (unix, unixMilli, rfc3339).
+func signHMAC(req *http.Request, authConfig map[string]string, now time.Time) error {
+	secret := authConfig["secret"]
+	if secret == "" {
+		return fmt.Errorf("HMAC signing requires a secret")
+	}
+
+	newHash, err := hmacHashFunc(authConfig["algorithm"])
+	if err != nil {
+		return err
+	}
+
+	timestamp, err := hmacTimestamp(authConfig["timestampFormat"], now)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(defaultString(authConfig["timestampHeader"], DefaultHMACTimestampHeader), timestamp)
+
+	body, err := requestBody(req)
+	if err != nil {
+		return fmt.Errorf("failed to read request body for signing: %w", err)
+	}
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	tmpl, err := template.New("hmacCanonical").Funcs(sprig.TxtFuncMap()).Parse(defaultString(authConfig["canonicalTemplate"], DefaultHMACCanonicalTemplate))
+	if err != nil {
+		return fmt.Errorf("failed to parse HMAC canonical template: %w", err)
+	}
+	var canonical bytes.Buffer
+	if err := tmpl.Execute(&canonical, hmacCanonicalData{
+		Method:    req.Method,
+		Host:      host,
+		Path:      path,
+		Query:     req.URL.RawQuery,
+		Timestamp: timestamp,
+		Body:      string(body),
+	}); err != nil {
+		return fmt.Errorf("failed to render HMAC canonical template: %w", err)
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(canonical.Bytes())
+	signature, err := encodeHMAC(mac.Sum(nil), authConfig["encoding"])
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(defaultString(authConfig["signatureHeader"], DefaultHMACSignatureHeader), authConfig["signaturePrefix"]+signature)
+	return nil
+}
+
+// VerifyHMACSignature checks a signature of a message, e.g. the timestamp and body of an incoming
+// webhook call, against the HMAC of the message keyed with the secret. The prefix, such as "sha256=",
+// is removed from the signature before comparing.
+func VerifyHMACSignature(message []byte, signature, secret, prefix, algorithm, encoding string) error {
+	if secret == "" {
+		return fmt.Errorf("HMAC verification requires a secret")
+	}
+	newHash, err := hmacHashFunc(algorithm)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(signature, prefix) {
+		return fmt.Errorf("signature does not start with '%s'", prefix)
+	}
+	signature = strings.TrimPrefix(signature, prefix)
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(message)
+	expected, err := encodeHMAC(mac.Sum(nil), encoding)
+	if err != nil {
+		return err
+	}
+	if encoding == "" || encoding == "hex" {
+		signature = strings.ToLower(signature)
+	}
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+// encodeHMAC encodes an HMAC sum, defaulting to hex
+func encodeHMAC(sum []byte, encoding string) (string, error) {
+	switch encoding {
+	case "", "hex":
+		return hex.EncodeToString(sum), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(sum), nil
+	default:
+		return "", fmt.Errorf("unsupported HMAC encoding: %s", encoding)
+	}
+}
+
+// hmacHashFunc returns the hash function of an HMAC algorithm, defaulting to SHA-256
+func hmacHashFunc(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha512":
+		return sha512.New, nil
+	case "sha1":
+		return sha1.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported HMAC algorithm: %s", algorithm)
+	}
+}
+
+// hmacTimestamp formats the signing time, defaulting to Unix seconds
+func hmacTimestamp(format string, now time.Time) (string, error) {
+	switch format {
+	case "", "unix":
+		return strconv.FormatInt(now.Unix(), 10), nil
+	case "unixMilli":
+		return strconv.FormatInt(now.UnixMilli(), 10), nil
+	case "rfc3339":
+		return now.UTC().Format(time.RFC3339), nil
+	default:
+		return "", fmt.Errorf("unsupported HMAC timestamp format: %s", format)
+	}
+}
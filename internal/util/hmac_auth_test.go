@@ -0,0 +1,104 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignHMAC(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+
+	t.Run("defaults", func(t *testing.T) {
+		req, err := http.NewRequest("POST", "https://partner.example.com/v1/orders?page=2", strings.NewReader(`{"id": 1}`))
+		require.NoError(t, err)
+
+		require.NoError(t, signHMAC(req, map[string]string{"secret": "s3cr3t"}, now))
+
+		mac := hmac.New(sha256.New, []byte("s3cr3t"))
+		mac.Write([]byte("POST\n/v1/orders\n1790857800\n{\"id\": 1}"))
+		assert.Equal(t, "1790857800", req.Header.Get("X-Timestamp"))
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), req.Header.Get("X-Signature"))
+	})
+
+	t.Run("custom canonical string, headers and encoding", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "https://partner.example.com/v1/orders?page=2", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, signHMAC(req, map[string]string{
+			"secret":            "s3cr3t",
+			"algorithm":         "sha512",
+			"encoding":          "base64",
+			"canonicalTemplate": "{{ .Timestamp }}.{{ .Method | lower }}.{{ .Host }}{{ .Path }}?{{ .Query }}",
+			"signatureHeader":   "X-Partner-Signature",
+			"signaturePrefix":   "sha512=",
+			"timestampHeader":   "X-Partner-Date",
+			"timestampFormat":   "rfc3339",
+		}, now))
+
+		mac := hmac.New(sha512.New, []byte("s3cr3t"))
+		mac.Write([]byte("2026-10-01T12:30:00Z.get.partner.example.com/v1/orders?page=2"))
+		assert.Equal(t, "2026-10-01T12:30:00Z", req.Header.Get("X-Partner-Date"))
+		assert.Equal(t, "sha512="+base64.StdEncoding.EncodeToString(mac.Sum(nil)), req.Header.Get("X-Partner-Signature"))
+	})
+
+	t.Run("unsupported algorithm", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "https://partner.example.com/", nil)
+		require.NoError(t, err)
+		assert.Error(t, signHMAC(req, map[string]string{"secret": "s3cr3t", "algorithm": "md5"}, now))
+	})
+}
+
+func TestRESTClient_ExecuteStatusUpdate_HMAC(t *testing.T) {
+	secret := "s3cr3t"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := make([]byte, r.ContentLength)
+		r.Body.Read(body)
+
+		// Verify the signature like a receiver would
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(r.Method + "\n" + r.URL.Path + "\n" + r.Header.Get("X-Timestamp") + "\n" + string(body)))
+		if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(r.Header.Get("X-Signature"))) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewRESTClient()
+	err := client.ExecuteStatusUpdate(t.Context(), HTTPStatusUpdateConfig{
+		URL:          server.URL + "/status/{{ .Item.id }}",
+		Method:       "PATCH",
+		BodyTemplate: `{"ready": true}`,
+		AuthType:     "hmac",
+		AuthConfig:   map[string]string{"secret": secret},
+	}, map[string]interface{}{"Item": map[string]interface{}{"id": "42"}})
+	require.NoError(t, err)
+}
+
+func TestVerifyHMACSignature(t *testing.T) {
+	body := []byte(`{"event": "user.created"}`)
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	assert.NoError(t, VerifyHMACSignature(body, signature, "s3cr3t", "", "", ""))
+	assert.NoError(t, VerifyHMACSignature(body, "sha256="+strings.ToUpper(signature), "s3cr3t", "sha256=", "sha256", "hex"))
+	assert.NoError(t, VerifyHMACSignature(body, base64.StdEncoding.EncodeToString(mac.Sum(nil)), "s3cr3t", "", "sha256", "base64"))
+
+	assert.Error(t, VerifyHMACSignature(body, signature, "other", "", "", ""), "wrong secret")
+	assert.Error(t, VerifyHMACSignature([]byte(`{}`), signature, "s3cr3t", "", "", ""), "modified body")
+	assert.Error(t, VerifyHMACSignature(body, signature, "s3cr3t", "sha256=", "", ""), "missing prefix")
+	assert.Error(t, VerifyHMACSignature(body, signature, "", "", "", ""), "missing secret")
+}
@@ -2,6 +2,10 @@ package util
 
 import (
 	"context"
+	"net/http"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // ItemResult represents a single item from an HTTP response.
@@ -10,18 +14,113 @@ type ItemResult map[string]interface{}
 // HTTPClient abstracts HTTP operations for the controller.
 type HTTPClient interface {
 	Execute(ctx context.Context, config HTTPConfig) ([]ItemResult, error)
+	Query(ctx context.Context, config HTTPConfig) (*QueryResult, error)
+	// Stream reads the events of a long-lived stream until the context is cancelled
+	Stream(ctx context.Context, config HTTPConfig, onEvent func(StreamEvent)) error
 	ExecuteStatusUpdate(ctx context.Context, config HTTPStatusUpdateConfig, resource interface{}) error
 }
 
+// SecretInvalidator is implemented by HTTP clients that cache credentials derived from Secrets,
+// such as OAuth2 tokens.
+type SecretInvalidator interface {
+	InvalidateSecret(secret types.NamespacedName)
+}
+
+// HTTPProber checks the health of HTTP endpoints.
+type HTTPProber interface {
+	Probe(ctx context.Context, config HTTPConfig) (int, error)
+}
+
 // HTTPConfig represents the configuration for HTTP requests.
 type HTTPConfig struct {
-	URL               string
-	Method            string
-	Headers           map[string]string
-	Body              string
-	AuthType          string
-	AuthConfig        map[string]string
-	ResponsePath      string
+	URL            string
+	Method         string
+	Headers        map[string]string
+	Body           string
+	AuthType       string
+	AuthConfig     map[string]string
+	ResponsePath   string
+	ResponseFormat string
+	// Transform is a jq program applied to every response before its items are extracted
+	Transform        string
+	Pagination       *PaginationConfig
+	ItemDetail       *ItemDetailConfig
+	GraphQL          *GraphQLConfig
+	Stream           *StreamConfig
+	Retry            *RetryConfig
+	TLS              *ResolvedTLSConfig
+	Timeout          time.Duration
+	MaxResponseBytes int64
+	// Validators of the previous response. When set, Query sends conditional request
+	// headers and reports whether the response is unchanged.
+	Validators *ResponseValidators
+	// StatusCodeHandling maps status codes to StatusCodeEmpty, StatusCodeUnchanged or StatusCodeError
+	StatusCodeHandling map[int]string
+	// OnRefreshToken receives OAuth2 refresh tokens rotated by the token endpoint
+	OnRefreshToken func(refreshToken string)
+}
+
+// ResponseValidators identify a response so that unchanged responses can be detected.
+type ResponseValidators struct {
+	ETag         string
+	LastModified string
+	// BodyHash is the SHA-256 hash of the response body, for servers that send no validators
+	BodyHash string
+}
+
+// QueryResult holds the outcome of a poll request.
+type QueryResult struct {
+	Items []ItemResult
+	// Unchanged is set when the response matches the validators of the request.
+	// Items are not parsed when the server answered 304 Not Modified.
+	Unchanged  bool
+	Validators ResponseValidators
+	// Response is the body of the response, decoded into JSON, and Header its headers.
+	// They are not set for paginated and GraphQL queries, which combine several responses.
+	Response []byte
+	Header   http.Header
+}
+
+// PaginationConfig represents the configuration for following paginated responses.
+type PaginationConfig struct {
+	Type        string
+	CursorPath  string
+	CursorParam string
+	OffsetParam string
+	PageParam   string
+	StartPage   *int
+	LimitParam  string
+	Limit       int
+	MaxPages    int
+}
+
+// ItemDetailConfig represents the configuration of the follow-up request made for every item.
+// URL, Headers and Body are templates receiving the item as .Item and its index as .Index.
+type ItemDetailConfig struct {
+	URL          string
+	Method       string
+	Headers      map[string]string
+	Body         string
+	ResponsePath string
+	Merge        string
+	Concurrency  int
+}
+
+// GraphQLConfig represents a GraphQL query sent as the JSON body of a POST request.
+type GraphQLConfig struct {
+	Query         string
+	Variables     map[string]interface{}
+	OperationName string
+	Pagination    *GraphQLPaginationConfig
+}
+
+// GraphQLPaginationConfig represents the configuration for following a cursor-based connection.
+type GraphQLPaginationConfig struct {
+	// ConnectionPath is the gjson path to the connection object holding pageInfo
+	ConnectionPath string
+	// CursorVariable is the variable receiving pageInfo.endCursor of the previous page
+	CursorVariable string
+	MaxPages       int
 }
 
 // HTTPStatusUpdateConfig represents the configuration for HTTP status update requests.
@@ -32,4 +131,11 @@ type HTTPStatusUpdateConfig struct {
 	BodyTemplate string
 	AuthType     string
 	AuthConfig   map[string]string
+	Retry        *RetryConfig
+	TLS          *ResolvedTLSConfig
+	Timeout      time.Duration
+	// MaxResponseBytes limits how much of an error response is read; successful responses are not read
+	MaxResponseBytes int64
+	// OnRefreshToken receives OAuth2 refresh tokens rotated by the token endpoint
+	OnRefreshToken func(refreshToken string)
 }
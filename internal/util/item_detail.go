@@ -0,0 +1,190 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+const (
+	// DefaultItemDetailConcurrency is the default number of concurrent item detail requests.
+	DefaultItemDetailConcurrency = 4
+)
+
+// ItemError describes the failure of a single item.
+type ItemError struct {
+	Index int
+	// Key of the item, if known
+	Key string
+	Err error
+}
+
+func (e ItemError) Error() string {
+	if e.Key != "" {
+		return fmt.Sprintf("item %d (key %s): %v", e.Index, e.Key, e.Err)
+	}
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+// ItemDetailError is returned when the detail request failed for one or more items.
+type ItemDetailError struct {
+	Total    int
+	Failures []ItemError
+}
+
+func (e *ItemDetailError) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		messages = append(messages, failure.Error())
+	}
+	return fmt.Sprintf("item detail request failed for %d of %d items: %s", len(e.Failures), e.Total, strings.Join(messages, "; "))
+}
+
+// itemDetailTemplates holds the parsed templates of an item detail request.
+type itemDetailTemplates struct {
+	url     *template.Template
+	headers map[string]*template.Template
+	body    *template.Template
+}
+
+// fetchItemDetails performs the item detail request for every item and combines the responses with
+// the items. The requests run concurrently, bounded by the configured concurrency. All items are
+// attempted, and the failures are reported together in an *ItemDetailError.
+func (r *RESTClient) fetchItemDetails(ctx context.Context, config HTTPConfig, items []ItemResult) ([]ItemResult, error) {
+	detail := config.ItemDetail
+	templates, err := parseItemDetailTemplates(detail)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := detail.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultItemDetailConcurrency
+	}
+
+	results := make([]ItemResult, len(items))
+	errs := make([]error, len(items))
+	semaphore := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, item := range items {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i], errs[i] = r.fetchItemDetail(ctx, config, templates, i, item)
+		}()
+	}
+	wg.Wait()
+
+	var failures []ItemError
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, ItemError{Index: i, Err: err})
+		}
+	}
+	if len(failures) > 0 {
+		return nil, &ItemDetailError{Total: len(items), Failures: failures}
+	}
+	return results, nil
+}
+
+// fetchItemDetail performs the item detail request for a single item.
+func (r *RESTClient) fetchItemDetail(ctx context.Context, config HTTPConfig, templates *itemDetailTemplates, index int, item ItemResult) (ItemResult, error) {
+	data := map[string]interface{}{
+		"Item":  item,
+		"Index": index,
+	}
+
+	url, err := executeTemplate(templates.url, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render URL: %w", err)
+	}
+	headers := make(map[string]string, len(templates.headers))
+	for key, tmpl := range templates.headers {
+		if headers[key], err = executeTemplate(tmpl, data); err != nil {
+			return nil, fmt.Errorf("failed to render header '%s': %w", key, err)
+		}
+	}
+	body, err := executeTemplate(templates.body, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render body: %w", err)
+	}
+
+	// The detail request shares the connection settings of the list request
+	detailConfig := config
+	detailConfig.URL = url
+	detailConfig.Method = config.ItemDetail.Method
+	detailConfig.Headers = headers
+	detailConfig.Body = body
+	detailConfig.ResponsePath = config.ItemDetail.ResponsePath
+	detailConfig.ResponseFormat = ""
+	detailConfig.Pagination = nil
+	detailConfig.ItemDetail = nil
+	detailConfig.GraphQL = nil
+	detailConfig.Validators = nil
+	detailConfig.StatusCodeHandling = nil
+
+	responseBody, _, err := r.fetch(ctx, detailConfig, url)
+	if err != nil {
+		return nil, err
+	}
+	details, err := r.parseResponse(responseBody, detailConfig.ResponsePath)
+	if err != nil {
+		return nil, err
+	}
+	if len(details) != 1 {
+		return nil, fmt.Errorf("expected a single object in the detail response, got %d", len(details))
+	}
+
+	if config.ItemDetail.Merge == "replace" {
+		return details[0], nil
+	}
+	merged := maps.Clone(item)
+	if merged == nil {
+		merged = ItemResult{}
+	}
+	maps.Copy(merged, details[0])
+	return merged, nil
+}
+
+// parseItemDetailTemplates parses the URL, header and body templates of an item detail request.
+func parseItemDetailTemplates(detail *ItemDetailConfig) (*itemDetailTemplates, error) {
+	parse := func(name, text string) (*template.Template, error) {
+		tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse item detail %s template: %w", name, err)
+		}
+		return tmpl, nil
+	}
+
+	templates := &itemDetailTemplates{headers: make(map[string]*template.Template, len(detail.Headers))}
+	var err error
+	if templates.url, err = parse("URL", detail.URL); err != nil {
+		return nil, err
+	}
+	for key, value := range detail.Headers {
+		if templates.headers[key], err = parse("header "+key, value); err != nil {
+			return nil, err
+		}
+	}
+	if templates.body, err = parse("body", detail.Body); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// executeTemplate renders a parsed template.
+func executeTemplate(tmpl *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,121 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTClient_Query_ItemDetail(t *testing.T) {
+	client := NewRESTClient()
+
+	newServer := func(t *testing.T, inFlight, maxInFlight *atomic.Int32) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/things" {
+				w.Write([]byte(`{"data": [{"id": "a", "name": "list-a"}, {"id": "b", "name": "list-b"}, {"id": "c", "name": "list-c"}]}`))
+				return
+			}
+			if inFlight != nil {
+				current := inFlight.Add(1)
+				defer inFlight.Add(-1)
+				for {
+					previous := maxInFlight.Load()
+					if current <= previous || maxInFlight.CompareAndSwap(previous, current) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			id := strings.TrimPrefix(r.URL.Path, "/things/")
+			if id == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			assert.Equal(t, "detail-"+id, r.Header.Get("X-Request"))
+			fmt.Fprintf(w, `{"thing": {"id": %q, "name": "detail-%s", "size": 3}}`, id, id)
+		}))
+	}
+
+	t.Run("merge details into items", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		server := newServer(t, &inFlight, &maxInFlight)
+		defer server.Close()
+
+		result, err := client.Query(context.Background(), HTTPConfig{
+			URL:          server.URL + "/things",
+			ResponsePath: "data",
+			ItemDetail: &ItemDetailConfig{
+				URL:          server.URL + "/things/{{ .Item.id }}",
+				Headers:      map[string]string{"X-Request": "detail-{{ .Item.id }}"},
+				ResponsePath: "thing",
+				Concurrency:  2,
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Items, 3)
+		assert.Equal(t, ItemResult{"id": "a", "name": "detail-a", "size": float64(3)}, result.Items[0])
+		assert.Equal(t, "detail-c", result.Items[2]["name"])
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	})
+
+	t.Run("replace items", func(t *testing.T) {
+		server := newServer(t, nil, nil)
+		defer server.Close()
+
+		result, err := client.Query(context.Background(), HTTPConfig{
+			URL:          server.URL + "/things",
+			ResponsePath: "data",
+			ItemDetail: &ItemDetailConfig{
+				URL:          server.URL + "/things/{{ .Item.id }}",
+				Headers:      map[string]string{"X-Request": "detail-{{ .Item.id }}"},
+				ResponsePath: "thing",
+				Merge:        "replace",
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Items, 3)
+		assert.Equal(t, ItemResult{"id": "b", "name": "detail-b", "size": float64(3)}, result.Items[1])
+	})
+
+	t.Run("failures are reported per item", func(t *testing.T) {
+		server := newServer(t, nil, nil)
+		defer server.Close()
+
+		_, err := client.Query(context.Background(), HTTPConfig{
+			URL:          server.URL + "/things",
+			ResponsePath: "data",
+			ItemDetail: &ItemDetailConfig{
+				URL:     server.URL + `/things/{{ if eq .Index 1 }}missing{{ else }}{{ .Item.id }}{{ end }}`,
+				Headers: map[string]string{"X-Request": "detail-{{ .Item.id }}"},
+			},
+		})
+		require.Error(t, err)
+		var detailErr *ItemDetailError
+		require.ErrorAs(t, err, &detailErr)
+		assert.Equal(t, 3, detailErr.Total)
+		require.Len(t, detailErr.Failures, 1)
+		assert.Equal(t, 1, detailErr.Failures[0].Index)
+		assert.Contains(t, err.Error(), "item detail request failed for 1 of 3 items: item 1: HTTP request failed with status 404")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		server := newServer(t, nil, nil)
+		defer server.Close()
+
+		_, err := client.Query(context.Background(), HTTPConfig{
+			URL:          server.URL + "/things",
+			ResponsePath: "data",
+			ItemDetail:   &ItemDetailConfig{URL: server.URL + "/things/{{ .Item.id"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to parse item detail URL template")
+	})
+}
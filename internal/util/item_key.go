@@ -0,0 +1,52 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// ItemKeyLabel labels the resources of an item with the value of ItemKeyLabelValue for its key
+	ItemKeyLabel = "konnektr.io/item-key"
+	// ItemKeyAnnotation holds the key of the item of a resource as it is
+	ItemKeyAnnotation = "konnektr.io/item-key"
+)
+
+// ItemKeyLabelValue returns the label value of an item key. Keys that are not valid label
+// values, e.g. because they are longer than 63 characters, are replaced by a hash.
+func ItemKeyLabelValue(key string) string {
+	if len(validation.IsValidLabelValue(key)) == 0 {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "sha256-" + hex.EncodeToString(sum[:])[:40]
+}
+
+// itemKeys returns the key at path of every item. Items without a key, or with the key of a
+// previous item, are reported as failures and have an empty key.
+func itemKeys(items []ItemResult, path string) ([]string, []ItemError) {
+	keys := make([]string, len(items))
+	var failures []ItemError
+	seen := make(map[string]int, len(items))
+	for i, item := range items {
+		key, ok, err := itemKey(item, path)
+		if err != nil {
+			failures = append(failures, ItemError{Index: i, Err: fmt.Errorf("key error: %w", err)})
+			continue
+		}
+		if !ok || key == "" {
+			failures = append(failures, ItemError{Index: i, Err: fmt.Errorf("key error: item key '%s' not found", path)})
+			continue
+		}
+		if first, ok := seen[key]; ok {
+			failures = append(failures, ItemError{Index: i, Key: key, Err: fmt.Errorf("key error: duplicate item key, also used by item %d", first)})
+			continue
+		}
+		seen[key] = i
+		keys[i] = key
+	}
+	return keys, failures
+}
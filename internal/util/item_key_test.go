@@ -0,0 +1,19 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestItemKeyLabelValue(t *testing.T) {
+	assert.Equal(t, "a1b2-c3", ItemKeyLabelValue("a1b2-c3"))
+	assert.Equal(t, "42", ItemKeyLabelValue("42"))
+
+	hashed := ItemKeyLabelValue("user@example.com")
+	assert.Regexp(t, `^sha256-[0-9a-f]{40}$`, hashed)
+	assert.Equal(t, hashed, ItemKeyLabelValue("user@example.com"))
+	assert.NotEqual(t, hashed, ItemKeyLabelValue("other@example.com"))
+	assert.Regexp(t, `^sha256-`, ItemKeyLabelValue(strings.Repeat("a", 64)))
+}
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+)
+
+// JoinConfig defines how the items of a source are matched to the primary items
+type JoinConfig struct {
+	// ItemKey is the gjson path to the key in a primary item
+	ItemKey string
+	// SourceKey is the gjson path to the key in a source item
+	SourceKey string
+}
+
+// JoinItems returns, for every primary item, the source items whose key equals the key of the
+// primary item. Keys are compared by their string representation. Items without a key match nothing.
+func JoinItems(items, sourceItems []ItemResult, join JoinConfig) ([][]ItemResult, error) {
+	index := make(map[string][]ItemResult)
+	for i, sourceItem := range sourceItems {
+		key, ok, err := itemKey(sourceItem, join.SourceKey)
+		if err != nil {
+			return nil, fmt.Errorf("source item %d: %w", i, err)
+		}
+		if ok {
+			index[key] = append(index[key], sourceItem)
+		}
+	}
+
+	joined := make([][]ItemResult, len(items))
+	for i, item := range items {
+		key, ok, err := itemKey(item, join.ItemKey)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		joined[i] = []ItemResult{}
+		if ok {
+			joined[i] = append(joined[i], index[key]...)
+		}
+	}
+	return joined, nil
+}
+
+// itemKey returns the string value at path in the item, and whether it exists
+func itemKey(item ItemResult, path string) (string, bool, error) {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to marshal item: %w", err)
+	}
+	result := gjson.GetBytes(data, path)
+	if !result.Exists() || result.Type == gjson.Null {
+		return "", false, nil
+	}
+	return result.String(), true, nil
+}
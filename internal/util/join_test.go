@@ -0,0 +1,33 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJoinItems(t *testing.T) {
+	items := []ItemResult{
+		{"id": float64(1), "name": "acme"},
+		{"id": float64(2), "name": "globex"},
+		{"name": "no-id"},
+	}
+	quotas := []ItemResult{
+		{"tenant": map[string]interface{}{"id": "1"}, "cpu": "4"},
+		{"tenant": map[string]interface{}{"id": "1"}, "memory": "8Gi"},
+		{"tenant": map[string]interface{}{"id": "3"}, "cpu": "2"},
+		{"cpu": "1"},
+	}
+
+	joined, err := JoinItems(items, quotas, JoinConfig{ItemKey: "id", SourceKey: "tenant.id"})
+	require.NoError(t, err)
+	require.Len(t, joined, 3)
+
+	require.Len(t, joined[0], 2)
+	assert.Equal(t, "4", joined[0][0]["cpu"])
+	assert.Equal(t, "8Gi", joined[0][1]["memory"])
+	assert.Empty(t, joined[1])
+	assert.NotNil(t, joined[1])
+	assert.Empty(t, joined[2])
+}
@@ -0,0 +1,343 @@
+package util
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// Supported OAuth2 grant types
+const (
+	OAuth2GrantClientCredentials = "client_credentials"
+	OAuth2GrantJWTBearer         = "jwt_bearer"
+	OAuth2GrantRefreshToken      = "refresh_token"
+	OAuth2GrantTokenExchange     = "token_exchange"
+)
+
+const (
+	// OAuth2ExtraParamPrefix prefixes additional token endpoint parameters in the auth config
+	OAuth2ExtraParamPrefix = "param."
+	// DefaultSubjectTokenType is the type of the subject token of a token exchange
+	DefaultSubjectTokenType = "urn:ietf:params:oauth:token-type:access_token"
+
+	jwtBearerGrantType      = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	tokenExchangeGrantType  = "urn:ietf:params:oauth:grant-type:token-exchange"
+	jwtBearerAssertionType  = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	jwtAssertionLifetime    = 5 * time.Minute
+	privateKeyJWTClientAuth = "private_key_jwt"
+)
+
+// getOAuth2Token returns an access token from the token endpoint using the configured grant.
+// Tokens are cached and only requested again shortly before they expire. Refresh tokens rotated
+// by the token endpoint are used for the next refresh and passed to onRefreshToken, which may be nil.
+// The token endpoint is requested with the TLS configuration of the request, which may be nil.
+//
+// Supported authConfig keys: tokenUrl, clientId, clientSecret, scopes, audience, grantType,
+// clientAuthentication (client_secret_basic, client_secret_post, private_key_jwt), privateKey,
+// keyId, subject, refreshToken, subjectToken, subjectTokenType, secretRef and param.<name>.
+func (r *RESTClient) getOAuth2Token(ctx context.Context, authConfig map[string]string, onRefreshToken func(string), tlsConfig *ResolvedTLSConfig) (string, error) {
+	clientID := authConfig["clientId"]
+	tokenURL := authConfig["tokenUrl"]
+	scopes := authConfig["scopes"]
+	grantType := defaultString(authConfig["grantType"], OAuth2GrantClientCredentials)
+
+	if tokenURL == "" {
+		return "", fmt.Errorf("OAuth2 requires tokenUrl")
+	}
+
+	// Cached tokens are discarded when any setting but the rotated refresh token changes
+	key := NewTokenCacheKey(tokenURL, clientID, scopes)
+	key.GrantType = grantType
+	key.SecretRef = authConfig["secretRef"]
+	if tlsConfig != nil {
+		key.TLS = tlsConfig.fingerprint()
+	}
+	credentials := make([]string, 0, 2*len(authConfig))
+	for _, name := range sortedKeys(authConfig) {
+		if name != "refreshToken" {
+			credentials = append(credentials, name, authConfig[name])
+		}
+	}
+	if grantType == OAuth2GrantRefreshToken {
+		r.refreshTokens.seed(key, authConfig["refreshToken"])
+	}
+
+	// Get token, reusing a cached one while it is valid. The token request must not be bound to
+	// the context of the first caller, as the cached source also serves later requests.
+	token, err := r.tokens.Token(key, credentials, func() (*oauth2.Token, error) {
+		config, err := r.tokenRequestConfig(key, authConfig)
+		if err != nil {
+			return nil, err
+		}
+		httpClient, err := r.httpClientFor(tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+		}
+
+		tokenCtx, cancel := context.WithTimeout(context.Background(), r.options.Timeout)
+		defer cancel()
+		token, err := config.Token(context.WithValue(tokenCtx, oauth2.HTTPClient, httpClient))
+		if err != nil {
+			return nil, err
+		}
+
+		if grantType == OAuth2GrantRefreshToken && r.refreshTokens.rotate(key, token.RefreshToken) && onRefreshToken != nil {
+			onRefreshToken(token.RefreshToken)
+		}
+		return token, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve OAuth2 token: %w", err)
+	}
+
+	return token.AccessToken, nil
+}
+
+// tokenRequestConfig builds the token request of the configured grant. The grant type and its
+// parameters are sent as endpoint parameters, which may override the client credentials grant type.
+// JWT assertions are signed for every request, as they are short-lived.
+func (r *RESTClient) tokenRequestConfig(key TokenCacheKey, authConfig map[string]string) (*clientcredentials.Config, error) {
+	config := &clientcredentials.Config{
+		ClientID:       authConfig["clientId"],
+		ClientSecret:   authConfig["clientSecret"],
+		TokenURL:       authConfig["tokenUrl"],
+		EndpointParams: url.Values{},
+	}
+	if scopes := authConfig["scopes"]; scopes != "" {
+		config.Scopes = strings.Fields(scopes)
+	}
+	if audience := authConfig["audience"]; audience != "" {
+		config.EndpointParams.Set("audience", audience)
+	}
+
+	switch key.GrantType {
+	case OAuth2GrantClientCredentials:
+		// Sent by clientcredentials
+	case OAuth2GrantJWTBearer:
+		subject := defaultString(authConfig["subject"], config.ClientID)
+		assertion, err := jwtAssertion(authConfig, config.ClientID, subject, config.TokenURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create JWT bearer assertion: %w", err)
+		}
+		config.EndpointParams.Set("grant_type", jwtBearerGrantType)
+		config.EndpointParams.Set("assertion", assertion)
+	case OAuth2GrantRefreshToken:
+		refreshToken := r.refreshTokens.current(key)
+		if refreshToken == "" {
+			return nil, fmt.Errorf("refresh_token grant requires a refresh token")
+		}
+		config.EndpointParams.Set("grant_type", "refresh_token")
+		config.EndpointParams.Set("refresh_token", refreshToken)
+	case OAuth2GrantTokenExchange:
+		if authConfig["subjectToken"] == "" {
+			return nil, fmt.Errorf("token_exchange grant requires a subject token")
+		}
+		config.EndpointParams.Set("grant_type", tokenExchangeGrantType)
+		config.EndpointParams.Set("subject_token", authConfig["subjectToken"])
+		config.EndpointParams.Set("subject_token_type", defaultString(authConfig["subjectTokenType"], DefaultSubjectTokenType))
+	default:
+		return nil, fmt.Errorf("unsupported OAuth2 grant type: %s", key.GrantType)
+	}
+
+	switch authConfig["clientAuthentication"] {
+	case "":
+		// Public clients send their client ID in the body
+		if config.ClientSecret == "" {
+			config.AuthStyle = oauth2.AuthStyleInParams
+		}
+	case "client_secret_basic":
+		config.AuthStyle = oauth2.AuthStyleInHeader
+	case "client_secret_post":
+		config.AuthStyle = oauth2.AuthStyleInParams
+	case privateKeyJWTClientAuth:
+		assertion, err := jwtAssertion(authConfig, config.ClientID, config.ClientID, config.TokenURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client assertion: %w", err)
+		}
+		config.ClientSecret = ""
+		config.AuthStyle = oauth2.AuthStyleInParams
+		config.EndpointParams.Set("client_assertion_type", jwtBearerAssertionType)
+		config.EndpointParams.Set("client_assertion", assertion)
+	default:
+		return nil, fmt.Errorf("unsupported OAuth2 client authentication: %s", authConfig["clientAuthentication"])
+	}
+
+	for name, value := range authConfig {
+		if param, ok := strings.CutPrefix(name, OAuth2ExtraParamPrefix); ok {
+			config.EndpointParams.Set(param, value)
+		}
+	}
+	return config, nil
+}
+
+// jwtAssertion returns a short-lived JWT signed with the private key of the auth config,
+// as used for JWT bearer grants and private_key_jwt client authentication (RFC 7523).
+func jwtAssertion(authConfig map[string]string, issuer, subject, audience string) (string, error) {
+	if authConfig["privateKey"] == "" {
+		return "", fmt.Errorf("a private key is required")
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	return signJWT(authConfig["privateKey"], authConfig["keyId"], map[string]interface{}{
+		"iss": issuer,
+		"sub": subject,
+		"aud": audience,
+		"iat": now.Unix(),
+		"exp": now.Add(jwtAssertionLifetime).Unix(),
+		"jti": hex.EncodeToString(jti),
+	})
+}
+
+// signJWT returns a JWT with the given claims, signed with a PEM-encoded RSA (RS256) or
+// ECDSA P-256 (ES256) private key.
+func signJWT(privateKeyPEM, keyID string, claims map[string]interface{}) (string, error) {
+	block, _ := pem.Decode([]byte(privateKeyPEM))
+	if block == nil {
+		return "", fmt.Errorf("private key is not PEM-encoded")
+	}
+	key, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]string{"typ": "JWT"}
+	if keyID != "" {
+		header["kid"] = keyID
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		header["alg"] = "RS256"
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return "", fmt.Errorf("unsupported ECDSA curve %s, only P-256 is supported", k.Curve.Params().Name)
+		}
+		header["alg"] = "ES256"
+	default:
+		return "", fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		if err != nil {
+			return "", err
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			return "", err
+		}
+		// JWS uses the fixed-size concatenation of r and s
+		signature = make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey parses a PKCS #8, PKCS #1 RSA or SEC 1 EC private key
+func parsePrivateKey(der []byte) (interface{}, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("failed to parse private key: unsupported format")
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// refreshTokenStore keeps the latest refresh token of every token source, as token endpoints
+// may rotate refresh tokens on every use. It is safe for concurrent use.
+type refreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[TokenCacheKey]*refreshTokenState
+}
+
+type refreshTokenState struct {
+	// seed is the refresh token last read from the Secret
+	seed string
+	// current is the refresh token to use for the next refresh
+	current string
+}
+
+func newRefreshTokenStore() *refreshTokenStore {
+	return &refreshTokenStore{tokens: make(map[TokenCacheKey]*refreshTokenState)}
+}
+
+// seed records the refresh token read from the Secret. A token that differs from the last one
+// read, e.g. because it was replaced manually or written back, is used for the next refresh.
+func (s *refreshTokenStore) seed(key TokenCacheKey, refreshToken string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, ok := s.tokens[key]; !ok || state.seed != refreshToken {
+		s.tokens[key] = &refreshTokenState{seed: refreshToken, current: refreshToken}
+	}
+}
+
+// current returns the refresh token to use for the next refresh
+func (s *refreshTokenStore) current(key TokenCacheKey) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state, ok := s.tokens[key]; ok {
+		return state.current
+	}
+	return ""
+}
+
+// rotate records a refresh token returned by the token endpoint and reports whether it changed
+func (s *refreshTokenStore) rotate(key TokenCacheKey, refreshToken string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.tokens[key]
+	if !ok || refreshToken == "" || refreshToken == state.current {
+		return false
+	}
+	state.current = refreshToken
+	return true
+}
@@ -0,0 +1,278 @@
+package util
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// tokenEndpoint records the form of every token request and answers with the given token response
+func tokenEndpoint(t *testing.T, forms *[]url.Values, response func(n int) string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		*forms = append(*forms, r.PostForm)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response(len(*forms))))
+	}))
+}
+
+// decodeJWT verifies the signature of a JWT with the public key and returns its header and claims
+func decodeJWT(t *testing.T, token string, publicKey crypto.PublicKey) (map[string]interface{}, map[string]interface{}) {
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		require.NoError(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+	case *ecdsa.PublicKey:
+		require.Len(t, signature, 64)
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		require.True(t, ecdsa.Verify(key, digest[:], r, s), "invalid ES256 signature")
+	}
+
+	var header, claims map[string]interface{}
+	for i, target := range []*map[string]interface{}{&header, &claims} {
+		decoded, err := base64.RawURLEncoding.DecodeString(parts[i])
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(decoded, target))
+	}
+	return header, claims
+}
+
+func TestRESTClient_OAuth2JWTBearerGrant(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+
+	var forms []url.Values
+	tokenServer := tokenEndpoint(t, &forms, func(int) string {
+		return `{"access_token": "jwt-token", "token_type": "Bearer", "expires_in": 3600}`
+	})
+	defer tokenServer.Close()
+
+	client := NewRESTClient()
+	token, err := client.getOAuth2Token(context.Background(), map[string]string{
+		"clientId":       "client",
+		"tokenUrl":       tokenServer.URL,
+		"grantType":      OAuth2GrantJWTBearer,
+		"privateKey":     privateKey,
+		"keyId":          "key-1",
+		"subject":        "service-user",
+		"audience":       "https://api.example.com",
+		"param.resource": "https://api.example.com/v1",
+	}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-token", token)
+
+	require.Len(t, forms, 1)
+	form := forms[0]
+	assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", form.Get("grant_type"))
+	assert.Equal(t, "https://api.example.com", form.Get("audience"))
+	assert.Equal(t, "https://api.example.com/v1", form.Get("resource"))
+	assert.Equal(t, "client", form.Get("client_id"))
+	header, claims := decodeJWT(t, form.Get("assertion"), &key.PublicKey)
+	assert.Equal(t, "RS256", header["alg"])
+	assert.Equal(t, "key-1", header["kid"])
+	assert.Equal(t, "client", claims["iss"])
+	assert.Equal(t, "service-user", claims["sub"])
+	assert.Equal(t, tokenServer.URL, claims["aud"])
+}
+
+func TestRESTClient_OAuth2PrivateKeyJWTClientAuthentication(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	var forms []url.Values
+	var authorization string
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		forms = append(forms, r.PostForm)
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "client-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+
+	client := NewRESTClient()
+	token, err := client.getOAuth2Token(context.Background(), map[string]string{
+		"clientId":             "client",
+		"tokenUrl":             tokenServer.URL,
+		"scopes":               "read",
+		"clientAuthentication": "private_key_jwt",
+		"privateKey":           string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+	}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "client-token", token)
+
+	require.Len(t, forms, 1)
+	form := forms[0]
+	assert.Empty(t, authorization)
+	assert.Equal(t, "client_credentials", form.Get("grant_type"))
+	assert.Equal(t, "read", form.Get("scope"))
+	assert.Equal(t, "client", form.Get("client_id"))
+	assert.Empty(t, form.Get("client_secret"))
+	assert.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", form.Get("client_assertion_type"))
+	header, claims := decodeJWT(t, form.Get("client_assertion"), &key.PublicKey)
+	assert.Equal(t, "ES256", header["alg"])
+	assert.Equal(t, "client", claims["iss"])
+	assert.Equal(t, "client", claims["sub"])
+}
+
+func TestRESTClient_OAuth2TokenExchangeGrant(t *testing.T) {
+	var forms []url.Values
+	tokenServer := tokenEndpoint(t, &forms, func(int) string {
+		return `{"access_token": "exchanged-token", "token_type": "Bearer", "expires_in": 3600}`
+	})
+	defer tokenServer.Close()
+
+	client := NewRESTClient()
+	token, err := client.getOAuth2Token(context.Background(), map[string]string{
+		"clientId":             "client",
+		"clientSecret":         "secret",
+		"clientAuthentication": "client_secret_post",
+		"tokenUrl":             tokenServer.URL,
+		"grantType":            OAuth2GrantTokenExchange,
+		"subjectToken":         "upstream-token",
+		"audience":             "downstream",
+	}, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "exchanged-token", token)
+
+	require.Len(t, forms, 1)
+	form := forms[0]
+	assert.Equal(t, "urn:ietf:params:oauth:grant-type:token-exchange", form.Get("grant_type"))
+	assert.Equal(t, "upstream-token", form.Get("subject_token"))
+	assert.Equal(t, "urn:ietf:params:oauth:token-type:access_token", form.Get("subject_token_type"))
+	assert.Equal(t, "downstream", form.Get("audience"))
+	assert.Equal(t, "secret", form.Get("client_secret"))
+}
+
+func TestRESTClient_OAuth2TokenEndpointTLS(t *testing.T) {
+	tokenServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "private-ca-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tokenServer.Certificate().Raw})
+
+	authConfig := map[string]string{
+		"clientId":     "client",
+		"clientSecret": "secret",
+		"tokenUrl":     tokenServer.URL,
+	}
+	client := NewRESTClient()
+
+	_, err := client.getOAuth2Token(context.Background(), authConfig, nil, nil)
+	require.Error(t, err, "the token endpoint certificate is not trusted without the TLS configuration")
+
+	token, err := client.getOAuth2Token(context.Background(), authConfig, nil, &ResolvedTLSConfig{CACert: caCert})
+	require.NoError(t, err)
+	assert.Equal(t, "private-ca-token", token)
+}
+
+func TestRESTClient_OAuth2RefreshTokenGrant(t *testing.T) {
+	// Tokens expire immediately, so every request refreshes and the endpoint rotates the refresh token
+	var forms []url.Values
+	tokenServer := tokenEndpoint(t, &forms, func(n int) string {
+		return fmt.Sprintf(`{"access_token": "access-%d", "refresh_token": "refresh-%d", "token_type": "Bearer", "expires_in": 1}`, n, n)
+	})
+	defer tokenServer.Close()
+
+	client := NewRESTClient()
+	authConfig := map[string]string{
+		"clientId":     "public-client",
+		"tokenUrl":     tokenServer.URL,
+		"grantType":    OAuth2GrantRefreshToken,
+		"refreshToken": "seed",
+	}
+	var written []string
+	onRefreshToken := func(refreshToken string) { written = append(written, refreshToken) }
+
+	for i := 1; i <= 2; i++ {
+		token, err := client.getOAuth2Token(context.Background(), authConfig, onRefreshToken, nil)
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("access-%d", i), token)
+	}
+	require.Len(t, forms, 2)
+	assert.Equal(t, "refresh_token", forms[0].Get("grant_type"))
+	assert.Equal(t, "public-client", forms[0].Get("client_id"))
+	assert.Equal(t, "seed", forms[0].Get("refresh_token"))
+	assert.Equal(t, "refresh-1", forms[1].Get("refresh_token"), "rotated refresh token is used for the next refresh")
+	assert.Equal(t, []string{"refresh-1", "refresh-2"}, written)
+
+	// The written back token is read from the Secret again and keeps the rotation going
+	authConfig["refreshToken"] = "refresh-2"
+	_, err := client.getOAuth2Token(context.Background(), authConfig, onRefreshToken, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh-2", forms[2].Get("refresh_token"))
+
+	// A refresh token replaced in the Secret takes precedence
+	authConfig["refreshToken"] = "replaced"
+	_, err = client.getOAuth2Token(context.Background(), authConfig, onRefreshToken, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "replaced", forms[3].Get("refresh_token"))
+}
+
+func TestAuthResolver_RefreshTokenWriter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "oauth", Namespace: "team-a"},
+			Data:       map[string][]byte{"clientId": []byte("client"), "token": []byte("seed")},
+		}).
+		Build()
+	resolver := NewAuthResolver(fakeClient, logr.Discard())
+
+	assert.Nil(t, resolver.RefreshTokenWriter(&ResolvedAuthConfig{AuthType: "oauth2", AuthConfig: map[string]string{
+		"secretRef": "team-a/oauth",
+	}}), "write-back is opt-in")
+
+	write := resolver.RefreshTokenWriter(&ResolvedAuthConfig{AuthType: "oauth2", AuthConfig: map[string]string{
+		"secretRef":       "team-a/oauth",
+		"refreshTokenKey": "token",
+	}})
+	require.NotNil(t, write)
+	write("rotated")
+
+	secret := &corev1.Secret{}
+	require.NoError(t, fakeClient.Get(context.Background(), client.ObjectKey{Namespace: "team-a", Name: "oauth"}, secret))
+	assert.Equal(t, "rotated", string(secret.Data["token"]))
+	assert.Equal(t, "client", string(secret.Data["clientId"]))
+	key, ok := WrittenBackRefreshTokenKey(secret)
+	assert.True(t, ok, "the write-back is recorded")
+	assert.Equal(t, "token", key)
+
+	secret.Data["token"] = []byte("replaced")
+	_, ok = WrittenBackRefreshTokenKey(secret)
+	assert.False(t, ok, "a replaced refresh token is not a write-back")
+}
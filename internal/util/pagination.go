@@ -0,0 +1,194 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// DefaultMaxPages is the safety cap on the number of pages fetched for a single poll.
+	DefaultMaxPages = 100
+)
+
+// linkEntryRegexp matches a single entry of an RFC 8288 Link header: <url>; params
+var linkEntryRegexp = regexp.MustCompile(`<([^>]*)>\s*((?:;\s*[^;,]+)*)`)
+
+// executePaginated follows all pages of a paginated response and merges the items.
+// The body of every page is written to bodies.
+func (r *RESTClient) executePaginated(ctx context.Context, config HTTPConfig, bodies io.Writer) ([]ItemResult, error) {
+	p := config.Pagination
+
+	maxPages := p.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+	startPage := 1
+	if p.StartPage != nil {
+		startPage = *p.StartPage
+	}
+
+	allItems := []ItemResult{}
+	offset := 0
+	page := startPage
+	cursor := ""
+	nextURL := ""
+
+	for fetched := 1; ; fetched++ {
+		requestURL, err := p.pageURL(config.URL, nextURL, offset, page, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build page URL: %w", err)
+		}
+
+		body, header, err := r.fetch(ctx, config, requestURL)
+		if errors.Is(err, errEmptyResponse) {
+			return allItems, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", fetched, err)
+		}
+
+		bodies.Write(body)
+
+		items, err := r.extractItems(ctx, body, config)
+		if err != nil {
+			return nil, fmt.Errorf("page %d: %w", fetched, err)
+		}
+		allItems = append(allItems, items...)
+
+		// Determine whether there is another page
+		hasNext := false
+		switch p.Type {
+		case "link":
+			nextURL, err = nextLinkURL(header, requestURL)
+			if err != nil {
+				return nil, fmt.Errorf("page %d: %w", fetched, err)
+			}
+			hasNext = nextURL != ""
+		case "cursor":
+			if p.CursorPath == "" {
+				return nil, fmt.Errorf("cursor pagination requires cursorPath")
+			}
+			next := gjson.GetBytes(body, p.CursorPath)
+			hasNext = next.Exists() && next.String() != "" && next.String() != cursor
+			cursor = next.String()
+		case "offset":
+			hasNext = !p.isLastPage(len(items))
+			offset += len(items)
+		case "page":
+			hasNext = !p.isLastPage(len(items))
+			page++
+		default:
+			return nil, fmt.Errorf("unsupported pagination type: %s", p.Type)
+		}
+
+		if !hasNext {
+			return allItems, nil
+		}
+		if fetched >= maxPages {
+			return nil, fmt.Errorf("pagination exceeded the maximum of %d pages", maxPages)
+		}
+	}
+}
+
+// pageURL returns the URL of the next page to fetch.
+func (p *PaginationConfig) pageURL(baseURL, nextURL string, offset, page int, cursor string) (string, error) {
+	params := map[string]string{}
+	switch p.Type {
+	case "link":
+		if nextURL != "" {
+			// Next links already carry all required query parameters
+			return nextURL, nil
+		}
+	case "cursor":
+		if cursor != "" {
+			params[defaultString(p.CursorParam, "cursor")] = cursor
+		}
+	case "offset":
+		params[defaultString(p.OffsetParam, "offset")] = strconv.Itoa(offset)
+	case "page":
+		params[defaultString(p.PageParam, "page")] = strconv.Itoa(page)
+	}
+	if p.Limit > 0 {
+		params[defaultString(p.LimitParam, "limit")] = strconv.Itoa(p.Limit)
+	}
+	return setQueryParams(baseURL, params)
+}
+
+// isLastPage reports whether a page with the given number of items is the last one.
+func (p *PaginationConfig) isLastPage(itemCount int) bool {
+	if itemCount == 0 {
+		return true
+	}
+	return p.Limit > 0 && itemCount < p.Limit
+}
+
+// setQueryParams returns rawURL with the given query parameters set.
+func setQueryParams(rawURL string, params map[string]string) (string, error) {
+	if len(params) == 0 {
+		return rawURL, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	query := u.Query()
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// nextLinkURL extracts the rel="next" URL from the Link response headers, resolved against requestURL.
+func nextLinkURL(header http.Header, requestURL string) (string, error) {
+	for _, value := range header.Values("Link") {
+		for _, match := range linkEntryRegexp.FindAllStringSubmatch(value, -1) {
+			if !linkHasRel(match[2], "next") {
+				continue
+			}
+			base, err := url.Parse(requestURL)
+			if err != nil {
+				return "", err
+			}
+			next, err := url.Parse(strings.TrimSpace(match[1]))
+			if err != nil {
+				return "", fmt.Errorf("invalid next link '%s': %w", match[1], err)
+			}
+			return base.ResolveReference(next).String(), nil
+		}
+	}
+	return "", nil
+}
+
+// linkHasRel reports whether the Link parameters contain the given relation type.
+func linkHasRel(params, rel string) bool {
+	for param := range strings.SplitSeq(params, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+			continue
+		}
+		for relType := range strings.FieldsSeq(strings.Trim(strings.TrimSpace(value), `"`)) {
+			if strings.EqualFold(relType, rel) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// defaultString returns value, or fallback if value is empty.
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,200 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(i int) *int { return &i }
+
+func TestRESTClient_Execute_Pagination(t *testing.T) {
+	client := NewRESTClient()
+
+	t.Run("link header pagination", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page == 0 {
+				page = 1
+			}
+			if page < 3 {
+				w.Header().Set("Link", fmt.Sprintf(`</items?page=%d>; rel="next", </items?page=3>; rel="last"`, page+1))
+			}
+			fmt.Fprintf(w, `[{"id": %d}]`, page)
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:        server.URL + "/items",
+			Pagination: &PaginationConfig{Type: "link"},
+		})
+		require.NoError(t, err)
+		require.Len(t, items, 3)
+		assert.Equal(t, float64(1), items[0]["id"])
+		assert.Equal(t, float64(3), items[2]["id"])
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		var cursors []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cursor := r.URL.Query().Get("after")
+			cursors = append(cursors, cursor)
+			switch cursor {
+			case "":
+				w.Write([]byte(`{"data": [{"id": 1}, {"id": 2}], "meta": {"next": "abc"}}`))
+			case "abc":
+				w.Write([]byte(`{"data": [{"id": 3}], "meta": {"next": null}}`))
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:          server.URL,
+			ResponsePath: "data",
+			Pagination: &PaginationConfig{
+				Type:        "cursor",
+				CursorPath:  "meta.next",
+				CursorParam: "after",
+			},
+		})
+		require.NoError(t, err)
+		assert.Len(t, items, 3)
+		assert.Equal(t, []string{"", "abc"}, cursors)
+	})
+
+	t.Run("offset pagination with limit", func(t *testing.T) {
+		data := []int{1, 2, 3, 4, 5}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			assert.Equal(t, 2, limit)
+			w.Write([]byte("["))
+			for i := offset; i < offset+limit && i < len(data); i++ {
+				if i > offset {
+					w.Write([]byte(","))
+				}
+				fmt.Fprintf(w, `{"id": %d}`, data[i])
+			}
+			w.Write([]byte("]"))
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:        server.URL,
+			Pagination: &PaginationConfig{Type: "offset", Limit: 2},
+		})
+		require.NoError(t, err)
+		require.Len(t, items, 5)
+		assert.Equal(t, float64(5), items[4]["id"])
+	})
+
+	t.Run("page number pagination stops on empty page", func(t *testing.T) {
+		var pages []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page := r.URL.Query().Get("p")
+			pages = append(pages, page)
+			if page == "2" {
+				w.Write([]byte(`[]`))
+				return
+			}
+			fmt.Fprintf(w, `[{"page": %s}]`, page)
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:        server.URL + "?filter=active",
+			Pagination: &PaginationConfig{Type: "page", PageParam: "p", StartPage: intPtr(0)},
+		})
+		require.NoError(t, err)
+		assert.Len(t, items, 2)
+		assert.Equal(t, []string{"0", "1", "2"}, pages)
+	})
+
+	t.Run("exceeding max pages fails", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"id": 1}]`))
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:        server.URL,
+			Pagination: &PaginationConfig{Type: "page", MaxPages: 3},
+		})
+		assert.Error(t, err)
+		assert.Nil(t, items)
+		assert.Contains(t, err.Error(), "maximum of 3 pages")
+	})
+
+	t.Run("error on later page fails the whole request", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("page") == "2" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`[{"id": 1}]`))
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:        server.URL,
+			Pagination: &PaginationConfig{Type: "page"},
+		})
+		assert.Error(t, err)
+		assert.Nil(t, items)
+		assert.Contains(t, err.Error(), "page 2")
+	})
+}
+
+func TestNextLinkURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		links    []string
+		expected string
+	}{
+		{
+			name:     "absolute next link",
+			links:    []string{`<https://api.example.com/items?page=2>; rel="next"`},
+			expected: "https://api.example.com/items?page=2",
+		},
+		{
+			name:     "relative next link among others",
+			links:    []string{`</items?page=1>; rel="prev", </items?page=3>; rel="next"`},
+			expected: "https://api.example.com/items?page=3",
+		},
+		{
+			name:     "multiple relation types",
+			links:    []string{`</items?page=5>; rel="next last"`},
+			expected: "https://api.example.com/items?page=5",
+		},
+		{
+			name:     "separate header values",
+			links:    []string{`</items?page=1>; rel="first"`, `</items?page=2>; rel=next`},
+			expected: "https://api.example.com/items?page=2",
+		},
+		{
+			name:     "no next link",
+			links:    []string{`</items?page=1>; rel="first"`},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			for _, link := range tt.links {
+				header.Add("Link", link)
+			}
+			next, err := nextLinkURL(header, "https://api.example.com/items?page=1")
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, next)
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrAuthentication marks probe failures caused by the credentials of the endpoint
+var ErrAuthentication = errors.New("authentication failed")
+
+// Probe sends a single request to check the health of an endpoint and returns the status code of
+// the response. Any response shows that the endpoint is reachable, so only credentials that cannot
+// be applied, e.g. because the OAuth2 token request failed, and 401 and 403 responses return an
+// error, wrapping ErrAuthentication. Network errors return a status code of 0.
+func (r *RESTClient) Probe(ctx context.Context, config HTTPConfig) (int, error) {
+	httpClient, err := r.httpClientFor(config.TLS)
+	if err != nil {
+		return 0, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, r.timeout(config.Timeout))
+	defer cancel()
+
+	req, err := r.buildRequest(probeCtx, config.URL, config.Method, config.Headers, "", "", nil, nil, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build request: %w", err)
+	}
+	if err := r.addAuthentication(req, config.AuthType, config.AuthConfig, config.OnRefreshToken, config.TLS); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrAuthentication, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, r.maxResponseBytes(config.MaxResponseBytes)))
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return resp.StatusCode, fmt.Errorf("%w: HTTP status %d", ErrAuthentication, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"net/http"
+)
+
+// Actions of HTTPConfig.StatusCodeHandling
+const (
+	StatusCodeEmpty     = "empty"
+	StatusCodeUnchanged = "unchanged"
+	StatusCodeError     = "error"
+)
+
+var (
+	// errNotModified is returned by fetch when the server answers 304 Not Modified.
+	errNotModified = errors.New("response not modified")
+	// errEmptyResponse is returned by fetch for status codes declared empty.
+	errEmptyResponse = errors.New("response declared empty by its status code")
+	// errUnchangedResponse is returned by fetch for status codes declared unchanged.
+	errUnchangedResponse = errors.New("response declared unchanged by its status code")
+)
+
+// Query performs the poll request and returns the response items with the validators of the response.
+// When config.Validators is set, If-None-Match and If-Modified-Since are sent and the result is marked
+// unchanged on a 304 response or when the body hash matches. Conditional headers are not sent for
+// paginated requests, as an unchanged first page does not imply unchanged later pages, nor for
+// GraphQL queries, which are sent as POST requests.
+// Status codes declared unchanged also mark the result unchanged, and status codes declared empty
+// return no items. An empty page ends pagination.
+// With an item detail request, the detail of every item is combined with the item; the body hash
+// only covers the list response.
+func (r *RESTClient) Query(ctx context.Context, config HTTPConfig) (*QueryResult, error) {
+	hash := sha256.New()
+	result := &QueryResult{}
+
+	if config.GraphQL != nil {
+		items, err := r.executeGraphQL(ctx, config, hash)
+		if errors.Is(err, errUnchangedResponse) {
+			return unchangedResult(config, nil), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Items = items
+	} else if config.Pagination != nil {
+		items, err := r.executePaginated(ctx, config, hash)
+		if errors.Is(err, errUnchangedResponse) {
+			return unchangedResult(config, nil), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Items = items
+	} else {
+		if config.Validators != nil {
+			config.Headers = conditionalHeaders(config.Headers, config.Validators)
+		}
+
+		body, header, err := r.fetch(ctx, config, config.URL)
+		if errors.Is(err, errNotModified) || errors.Is(err, errUnchangedResponse) {
+			return unchangedResult(config, header), nil
+		}
+		if errors.Is(err, errEmptyResponse) {
+			result.Items = []ItemResult{}
+			result.Header = header
+			result.Validators.updateFrom(header)
+		} else {
+			if err != nil {
+				return nil, err
+			}
+			hash.Write(body)
+			result.Response = body
+			result.Header = header
+			result.Validators.updateFrom(header)
+			if result.Items, err = r.extractItems(ctx, body, config); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	result.Validators.BodyHash = hex.EncodeToString(hash.Sum(nil))
+	result.Unchanged = config.Validators != nil && config.Validators.BodyHash == result.Validators.BodyHash
+
+	if config.ItemDetail != nil {
+		items, err := r.fetchItemDetails(ctx, config, result.Items)
+		if err != nil {
+			return nil, err
+		}
+		result.Items = items
+	}
+	return result, nil
+}
+
+// unchangedResult returns the result of an unchanged response, keeping the validators of the previous response
+func unchangedResult(config HTTPConfig, header http.Header) *QueryResult {
+	result := &QueryResult{Unchanged: true}
+	if config.Validators != nil {
+		result.Validators = *config.Validators
+	}
+	result.Validators.updateFrom(header)
+	return result
+}
+
+// conditionalHeaders returns a copy of headers with the conditional request headers for validators.
+func conditionalHeaders(headers map[string]string, validators *ResponseValidators) map[string]string {
+	conditional := maps.Clone(headers)
+	if conditional == nil {
+		conditional = make(map[string]string)
+	}
+	if validators.ETag != "" {
+		conditional["If-None-Match"] = validators.ETag
+	}
+	if validators.LastModified != "" {
+		conditional["If-Modified-Since"] = validators.LastModified
+	}
+	return conditional
+}
+
+// updateFrom records the validators sent in the response headers.
+func (v *ResponseValidators) updateFrom(header http.Header) {
+	if etag := header.Get("ETag"); etag != "" {
+		v.ETag = etag
+	}
+	if lastModified := header.Get("Last-Modified"); lastModified != "" {
+		v.LastModified = lastModified
+	}
+}
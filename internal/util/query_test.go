@@ -0,0 +1,199 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTClient_Query_Conditional(t *testing.T) {
+	client := NewRESTClient()
+
+	t.Run("etag and last-modified", func(t *testing.T) {
+		const etag = `"v1"`
+		const lastModified = "Wed, 21 Oct 2026 07:28:00 GMT"
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag {
+				assert.Equal(t, lastModified, r.Header.Get("If-Modified-Since"))
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Last-Modified", lastModified)
+			w.Write([]byte(`[{"id": 1}]`))
+		}))
+		defer server.Close()
+
+		config := HTTPConfig{URL: server.URL}
+		first, err := client.Query(context.Background(), config)
+		require.NoError(t, err)
+		assert.False(t, first.Unchanged)
+		assert.Len(t, first.Items, 1)
+		assert.Equal(t, etag, first.Validators.ETag)
+		assert.Equal(t, lastModified, first.Validators.LastModified)
+		assert.NotEmpty(t, first.Validators.BodyHash)
+		assert.JSONEq(t, `[{"id": 1}]`, string(first.Response))
+		assert.Equal(t, etag, first.Header.Get("ETag"))
+
+		config.Validators = &first.Validators
+		second, err := client.Query(context.Background(), config)
+		require.NoError(t, err)
+		assert.True(t, second.Unchanged)
+		assert.Nil(t, second.Items)
+		assert.Equal(t, first.Validators, second.Validators)
+	})
+
+	t.Run("body hash without validators", func(t *testing.T) {
+		body := `[{"id": 1}]`
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("If-None-Match"))
+			assert.Empty(t, r.Header.Get("If-Modified-Since"))
+			w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		config := HTTPConfig{URL: server.URL}
+		first, err := client.Query(context.Background(), config)
+		require.NoError(t, err)
+		assert.False(t, first.Unchanged)
+
+		config.Validators = &first.Validators
+		second, err := client.Query(context.Background(), config)
+		require.NoError(t, err)
+		assert.True(t, second.Unchanged)
+		assert.Len(t, second.Items, 1)
+
+		body = `[{"id": 1}, {"id": 2}]`
+		third, err := client.Query(context.Background(), config)
+		require.NoError(t, err)
+		assert.False(t, third.Unchanged)
+		assert.Len(t, third.Items, 2)
+		assert.NotEqual(t, first.Validators.BodyHash, third.Validators.BodyHash)
+	})
+
+	t.Run("paginated requests are not conditional", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("If-None-Match"))
+			w.Header().Set("ETag", `"v1"`)
+			if r.URL.Query().Get("page") == "1" {
+				w.Write([]byte(`[{"id": 1}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		config := HTTPConfig{URL: server.URL, Pagination: &PaginationConfig{Type: "page"}}
+		first, err := client.Query(context.Background(), config)
+		require.NoError(t, err)
+		assert.Len(t, first.Items, 1)
+
+		config.Validators = &ResponseValidators{ETag: `"v1"`, BodyHash: first.Validators.BodyHash}
+		second, err := client.Query(context.Background(), config)
+		require.NoError(t, err)
+		assert.True(t, second.Unchanged)
+	})
+
+	t.Run("execute ignores validators", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("If-None-Match"))
+			w.Write([]byte(`[{"id": 1}]`))
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:        server.URL,
+			Validators: &ResponseValidators{ETag: `"v1"`},
+		})
+		require.NoError(t, err)
+		assert.Len(t, items, 1)
+	})
+
+	t.Run("not modified without validators", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", `"v2"`)
+			w.WriteHeader(http.StatusNotModified)
+		}))
+		defer server.Close()
+
+		// If-None-Match set by the request instead of the validators
+		result, err := client.Query(context.Background(), HTTPConfig{
+			URL:     server.URL,
+			Headers: map[string]string{"If-None-Match": `"v2"`},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Unchanged)
+		assert.Equal(t, `"v2"`, result.Validators.ETag)
+	})
+}
+
+func TestRESTClient_Query_StatusCodeHandling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case "/empty":
+			w.WriteHeader(http.StatusNoContent)
+		case "/busy":
+			w.WriteHeader(http.StatusConflict)
+		case "/accepted":
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"status": "pending"}`))
+		case "/pages":
+			if r.URL.Query().Get("page") == "2" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"data": [{"id": 1}, {"id": 2}]}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewRESTClient()
+	handling := map[int]string{
+		http.StatusNotFound:  StatusCodeEmpty,
+		http.StatusNoContent: StatusCodeEmpty,
+		http.StatusConflict:  StatusCodeUnchanged,
+		http.StatusAccepted:  StatusCodeError,
+	}
+	query := func(config HTTPConfig) (*QueryResult, error) {
+		config.URL = server.URL + config.URL
+		config.ResponsePath = "data"
+		config.StatusCodeHandling = handling
+		return client.Query(context.Background(), config)
+	}
+
+	for _, path := range []string{"/missing", "/empty"} {
+		result, err := query(HTTPConfig{URL: path})
+		require.NoError(t, err, path)
+		assert.False(t, result.Unchanged, path)
+		assert.Equal(t, []ItemResult{}, result.Items, path)
+	}
+
+	result, err := query(HTTPConfig{URL: "/busy"})
+	require.NoError(t, err)
+	assert.True(t, result.Unchanged)
+
+	_, err = query(HTTPConfig{URL: "/accepted"})
+	assert.ErrorContains(t, err, "status 202")
+
+	t.Run("empty page ends pagination", func(t *testing.T) {
+		result, err := query(HTTPConfig{URL: "/pages", Pagination: &PaginationConfig{Type: "page", PageParam: "page", LimitParam: "size", Limit: 2}})
+		require.NoError(t, err)
+		assert.Len(t, result.Items, 2)
+	})
+
+	t.Run("unchanged fails Execute", func(t *testing.T) {
+		_, err := client.Execute(context.Background(), HTTPConfig{URL: server.URL + "/busy", StatusCodeHandling: handling})
+		assert.Error(t, err)
+	})
+
+	t.Run("undeclared codes fail", func(t *testing.T) {
+		_, err := client.Query(context.Background(), HTTPConfig{URL: server.URL + "/missing"})
+		assert.ErrorContains(t, err, "status 404")
+	})
+}
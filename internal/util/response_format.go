@@ -0,0 +1,210 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Supported response formats
+const (
+	ResponseFormatJSON   = "json"
+	ResponseFormatYAML   = "yaml"
+	ResponseFormatXML    = "xml"
+	ResponseFormatCSV    = "csv"
+	ResponseFormatNDJSON = "ndjson"
+)
+
+// decodeResponse converts a response body in the given format into JSON. Without a format,
+// the format is detected from the Content-Type header, defaulting to JSON.
+func decodeResponse(body []byte, format, contentType string) ([]byte, error) {
+	if format == "" {
+		format = detectResponseFormat(contentType)
+	}
+	if format == ResponseFormatJSON || len(bytes.TrimSpace(body)) == 0 {
+		return body, nil
+	}
+
+	var decoded []byte
+	var err error
+	switch format {
+	case ResponseFormatYAML:
+		decoded, err = yaml.YAMLToJSON(body)
+	case ResponseFormatXML:
+		decoded, err = decodeXML(body)
+	case ResponseFormatCSV:
+		decoded, err = decodeCSV(body)
+	case ResponseFormatNDJSON:
+		decoded, err = decodeNDJSON(body)
+	default:
+		return nil, fmt.Errorf("unsupported response format '%s'", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s response: %w", format, err)
+	}
+	return decoded, nil
+}
+
+// ParseItems decodes a body in the given format, applies the transform, if any, and extracts
+// the items at the response path, the same way as the body of a poll response.
+func ParseItems(ctx context.Context, body []byte, format, contentType, transform, responsePath string) ([]ItemResult, error) {
+	decoded, err := decodeResponse(body, format, contentType)
+	if err != nil {
+		return nil, err
+	}
+	return (*RESTClient)(nil).extractItems(ctx, decoded, HTTPConfig{Transform: transform, ResponsePath: responsePath})
+}
+
+// detectResponseFormat returns the response format of a Content-Type, defaulting to JSON
+func detectResponseFormat(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ResponseFormatJSON
+	}
+	switch {
+	case mediaType == "application/x-ndjson" || mediaType == "application/ndjson" ||
+		mediaType == "application/jsonl" || mediaType == "application/x-jsonlines":
+		return ResponseFormatNDJSON
+	case mediaType == "application/yaml" || mediaType == "application/x-yaml" ||
+		mediaType == "text/yaml" || mediaType == "text/x-yaml" || strings.HasSuffix(mediaType, "+yaml"):
+		return ResponseFormatYAML
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return ResponseFormatXML
+	case mediaType == "text/csv" || mediaType == "application/csv":
+		return ResponseFormatCSV
+	default:
+		return ResponseFormatJSON
+	}
+}
+
+// decodeNDJSON converts newline-delimited JSON into a JSON array. Empty lines are skipped.
+func decodeNDJSON(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	count := 0
+	for i, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return nil, fmt.Errorf("line %d is not valid JSON", i+1)
+		}
+		if count > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(line)
+		count++
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// decodeCSV converts CSV into a JSON array of objects. The first row holds the field names,
+// and every following row becomes an object mapping the field names to the string values.
+func decodeCSV(body []byte) ([]byte, error) {
+	reader := csv.NewReader(bytes.NewReader(body))
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []map[string]string{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]string, len(header))
+		for i, name := range header {
+			row[name] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return json.Marshal(rows)
+}
+
+// decodeXML converts an XML document into a JSON object using the following convention:
+//   - the root element becomes the only field of the object, named after the element
+//   - attributes become fields named "@<attribute>"
+//   - child elements become fields named after the element; repeated elements become arrays
+//   - text of an element with attributes or children becomes the field "#text"
+//   - an element with only text becomes a string
+//
+// Namespace prefixes are dropped and all values are strings.
+func decodeXML(body []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("no root element")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			value, err := decodeXMLElement(decoder, start)
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(map[string]interface{}{start.Name.Local: value})
+		}
+	}
+}
+
+// decodeXMLElement decodes the element started by start, see decodeXML
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	element := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+			continue
+		}
+		element["@"+attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			switch existing := element[t.Name.Local].(type) {
+			case nil:
+				element[t.Name.Local] = child
+			case []interface{}:
+				element[t.Name.Local] = append(existing, child)
+			default:
+				element[t.Name.Local] = []interface{}{existing, child}
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			trimmed := strings.TrimSpace(text.String())
+			if len(element) == 0 {
+				return trimmed, nil
+			}
+			if trimmed != "" {
+				element["#text"] = trimmed
+			}
+			return element, nil
+		}
+	}
+}
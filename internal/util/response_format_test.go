@@ -0,0 +1,131 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		format      string
+		contentType string
+		expected    string
+		wantErr     bool
+	}{
+		{
+			name:        "json is passed through",
+			body:        `[{"id": 1}]`,
+			contentType: "application/json",
+			expected:    `[{"id": 1}]`,
+		},
+		{
+			name:     "unknown content type defaults to json",
+			body:     `[{"id": 1}]`,
+			expected: `[{"id": 1}]`,
+		},
+		{
+			name:        "yaml detected from content type",
+			body:        "items:\n  - id: 1\n    name: a\n",
+			contentType: "application/yaml; charset=utf-8",
+			expected:    `{"items":[{"id":1,"name":"a"}]}`,
+		},
+		{
+			name:        "ndjson skips empty lines",
+			body:        "{\"id\": 1}\n\n{\"id\": 2}\n",
+			contentType: "application/x-ndjson",
+			expected:    `[{"id": 1},{"id": 2}]`,
+		},
+		{
+			name:    "invalid ndjson line",
+			body:    "{\"id\": 1}\nnot json\n",
+			format:  ResponseFormatNDJSON,
+			wantErr: true,
+		},
+		{
+			name:        "csv maps header to values",
+			body:        "id,name\n1,alice\n2,\"bob, jr\"\n",
+			contentType: "text/csv",
+			expected:    `[{"id":"1","name":"alice"},{"id":"2","name":"bob, jr"}]`,
+		},
+		{
+			name:    "csv with missing fields",
+			body:    "id,name\n1\n",
+			format:  ResponseFormatCSV,
+			wantErr: true,
+		},
+		{
+			name: "xml attributes, text and repeated elements",
+			body: `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Things</title>
+  <entry id="1"><name>a</name></entry>
+  <entry id="2"><name lang="en">b</name></entry>
+</feed>`,
+			contentType: "application/atom+xml",
+			expected:    `{"feed":{"entry":[{"@id":"1","name":"a"},{"@id":"2","name":{"#text":"b","@lang":"en"}}],"title":"Things"}}`,
+		},
+		{
+			name:     "explicit format overrides content type",
+			body:     "<items><item>1</item></items>",
+			format:   ResponseFormatXML,
+			expected: `{"items":{"item":"1"}}`,
+		},
+		{
+			name:    "unsupported format",
+			body:    "x",
+			format:  "toml",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := decodeResponse([]byte(tt.body), tt.format, tt.contentType)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(decoded))
+		})
+	}
+}
+
+func TestRESTClient_Execute_ResponseFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.Write([]byte(`<users><user id="1"><name>alice</name></user><user id="2"><name>bob</name></user></users>`))
+	}))
+	defer server.Close()
+
+	client := NewRESTClient()
+	items, err := client.Execute(context.Background(), HTTPConfig{
+		URL:          server.URL,
+		ResponsePath: "users.user",
+	})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "1", items[0]["@id"])
+	assert.Equal(t, "bob", items[1]["name"])
+}
+
+func TestParseItems(t *testing.T) {
+	items, err := ParseItems(context.Background(), []byte("users:\n- id: 1\n- id: 2\n"), "", "application/yaml", "", "users")
+	require.NoError(t, err)
+	assert.Equal(t, []ItemResult{{"id": float64(1)}, {"id": float64(2)}}, items)
+
+	_, err = ParseItems(context.Background(), []byte(`{"users": []}`), ResponseFormatJSON, "", "", "groups")
+	assert.Error(t, err)
+
+	items, err = ParseItems(context.Background(), []byte("<users><user id=\"1\"/><user id=\"2\"/></users>"), ResponseFormatXML, "",
+		`[.users.user[] | {id: (."@id" | tonumber)}]`, "")
+	require.NoError(t, err)
+	assert.Equal(t, []ItemResult{{"id": float64(1)}, {"id": float64(2)}}, items)
+}
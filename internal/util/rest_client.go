@@ -8,52 +8,169 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
 	"github.com/Masterminds/sprig/v3"
 	"github.com/tidwall/gjson"
-	"golang.org/x/oauth2/clientcredentials"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// DefaultRequestTimeout is the timeout of a single HTTP request attempt.
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultMaxResponseBytes is the maximum size of a response body read into memory.
+	DefaultMaxResponseBytes int64 = 10 << 20
 )
 
 // RESTClient implements HTTPClient for REST APIs.
 type RESTClient struct {
-	client *http.Client
+	client        *http.Client
+	tokens        *TokenCache
+	refreshTokens *refreshTokenStore
+	options       RESTClientOptions
+
+	mu         sync.Mutex
+	tlsClients map[string]*http.Client
+}
+
+// RESTClientOptions holds the operator-wide defaults of a REST client.
+// Requests can override them through their configuration.
+type RESTClientOptions struct {
+	// Timeout of a single HTTP request attempt. Defaults to DefaultRequestTimeout.
+	Timeout time.Duration
+	// MaxResponseBytes is the maximum size of a response body. Defaults to DefaultMaxResponseBytes.
+	MaxResponseBytes int64
 }
 
 // NewRESTClient creates a new REST client.
 func NewRESTClient() *RESTClient {
+	return NewRESTClientWithOptions(RESTClientOptions{})
+}
+
+// NewRESTClientWithOptions creates a new REST client with the given defaults.
+func NewRESTClientWithOptions(options RESTClientOptions) *RESTClient {
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultRequestTimeout
+	}
+	if options.MaxResponseBytes <= 0 {
+		options.MaxResponseBytes = DefaultMaxResponseBytes
+	}
 	return &RESTClient{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		// Timeouts are applied per attempt through the request context
+		client:        &http.Client{},
+		tokens:        NewTokenCache(),
+		refreshTokens: newRefreshTokenStore(),
+		options:       options,
+		tlsClients:    make(map[string]*http.Client),
 	}
 }
 
+// InvalidateSecret discards cached tokens obtained with the credentials of the Secret.
+func (r *RESTClient) InvalidateSecret(secret types.NamespacedName) {
+	r.tokens.InvalidateSecret(secret.String())
+}
+
 // Execute performs an HTTP request and returns the response items.
 func (r *RESTClient) Execute(ctx context.Context, config HTTPConfig) ([]ItemResult, error) {
-	req, err := r.buildRequest(ctx, config.URL, config.Method, config.Headers, config.Body, config.AuthType, config.AuthConfig)
+	config.Validators = nil
+	result, err := r.Query(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	if result.Unchanged {
+		return nil, errUnchangedResponse
+	}
+	return result.Items, nil
+}
+
+// fetch performs a single HTTP request and returns the response body and headers.
+func (r *RESTClient) fetch(ctx context.Context, config HTTPConfig, url string) ([]byte, http.Header, error) {
+	httpClient, err := r.httpClientFor(config.TLS)
 	if err != nil {
-		return nil, fmt.Errorf("failed to build request: %w", err)
+		return nil, nil, fmt.Errorf("invalid TLS configuration: %w", err)
 	}
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(ctx, httpClient, config.Retry, r.timeout(config.Timeout), func(ctx context.Context) (*http.Request, error) {
+		req, err := r.buildRequest(ctx, url, config.Method, config.Headers, config.Body, config.AuthType, config.AuthConfig, config.OnRefreshToken, config.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
+		return req, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		return nil, nil, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, resp.Header, errNotModified
+	}
+
+	maxBytes := r.maxResponseBytes(config.MaxResponseBytes)
+	switch config.StatusCodeHandling[resp.StatusCode] {
+	case StatusCodeEmpty:
+		return nil, resp.Header, errEmptyResponse
+	case StatusCodeUnchanged:
+		return nil, resp.Header, errUnchangedResponse
+	case StatusCodeError:
+		return nil, nil, fmt.Errorf("HTTP request failed with status %d: %s", resp.StatusCode, readErrorBody(resp, maxBytes))
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("HTTP request failed with status %d: %s", resp.StatusCode, string(body))
+		return nil, nil, fmt.Errorf("HTTP request failed with status %d: %s", resp.StatusCode, readErrorBody(resp, maxBytes))
+	}
+
+	bodyBytes, err := readBody(resp, maxBytes)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	// Decode other formats into JSON, so that response paths and templates work the same
+	bodyBytes, err = decodeResponse(bodyBytes, config.ResponseFormat, resp.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return bodyBytes, resp.Header, nil
+}
+
+// readBody reads the response body, failing if it is larger than maxBytes.
+func readBody(resp *http.Response, maxBytes int64) ([]byte, error) {
+	if resp.ContentLength > maxBytes {
+		return nil, fmt.Errorf("response body of %d bytes exceeds the maximum size of %d bytes", resp.ContentLength, maxBytes)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	if int64(len(body)) > maxBytes {
+		return nil, fmt.Errorf("response body exceeds the maximum size of %d bytes", maxBytes)
+	}
+	return body, nil
+}
+
+// readErrorBody reads at most maxBytes of an error response, so that oversized
+// error bodies are truncated rather than dropped from the error message.
+func readErrorBody(resp *http.Response, maxBytes int64) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxBytes))
+	return string(body)
+}
+
+// timeout returns the request timeout, falling back to the client default.
+func (r *RESTClient) timeout(timeout time.Duration) time.Duration {
+	if timeout > 0 {
+		return timeout
+	}
+	return r.options.Timeout
+}
 
-	return r.parseResponse(bodyBytes, config.ResponsePath)
+// maxResponseBytes returns the response size limit, falling back to the client default.
+func (r *RESTClient) maxResponseBytes(maxBytes int64) int64 {
+	if maxBytes > 0 {
+		return maxBytes
+	}
+	return r.options.MaxResponseBytes
 }
 
 // ExecuteStatusUpdate performs an HTTP request to update resource status.
@@ -84,27 +201,33 @@ func (r *RESTClient) ExecuteStatusUpdate(ctx context.Context, config HTTPStatusU
 		return fmt.Errorf("failed to render URL template: %w", err)
 	}
 
-	req, err := r.buildRequest(ctx, urlBuffer.String(), config.Method, config.Headers, bodyBuffer.String(), config.AuthType, config.AuthConfig)
+	httpClient, err := r.httpClientFor(config.TLS)
 	if err != nil {
-		return fmt.Errorf("failed to build status update request: %w", err)
+		return fmt.Errorf("invalid TLS configuration: %w", err)
 	}
 
-	resp, err := r.client.Do(req)
+	resp, err := r.do(ctx, httpClient, config.Retry, r.timeout(config.Timeout), func(ctx context.Context) (*http.Request, error) {
+		req, err := r.buildRequest(ctx, urlBuffer.String(), config.Method, config.Headers, bodyBuffer.String(), config.AuthType, config.AuthConfig, config.OnRefreshToken, config.TLS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build status update request: %w", err)
+		}
+		return req, nil
+	})
 	if err != nil {
 		return fmt.Errorf("status update HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("status update HTTP request failed with status %d: %s", resp.StatusCode, string(body))
+		return fmt.Errorf("status update HTTP request failed with status %d: %s", resp.StatusCode, readErrorBody(resp, r.maxResponseBytes(config.MaxResponseBytes)))
 	}
 
 	return nil
 }
 
 // buildRequest constructs an HTTP request with authentication.
-func (r *RESTClient) buildRequest(ctx context.Context, url, method string, headers map[string]string, body, authType string, authConfig map[string]string) (*http.Request, error) {
+// onRefreshToken receives OAuth2 refresh tokens rotated by the token endpoint and may be nil.
+func (r *RESTClient) buildRequest(ctx context.Context, url, method string, headers map[string]string, body, authType string, authConfig map[string]string, onRefreshToken func(string), tlsConfig *ResolvedTLSConfig) (*http.Request, error) {
 	if method == "" {
 		method = "GET"
 	}
@@ -130,7 +253,7 @@ func (r *RESTClient) buildRequest(ctx context.Context, url, method string, heade
 	}
 
 	// Add authentication
-	err = r.addAuthentication(req, authType, authConfig)
+	err = r.addAuthentication(req, authType, authConfig, onRefreshToken, tlsConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to add authentication: %w", err)
 	}
@@ -138,8 +261,10 @@ func (r *RESTClient) buildRequest(ctx context.Context, url, method string, heade
 	return req, nil
 }
 
-// addAuthentication adds authentication to the request.
-func (r *RESTClient) addAuthentication(req *http.Request, authType string, authConfig map[string]string) error {
+// addAuthentication adds authentication to the request. OAuth2 token requests use the TLS
+// configuration of the request, as token endpoints are often behind the same private CA.
+// awssigv4 and hmac sign the complete request, so this must be the last step of building the request.
+func (r *RESTClient) addAuthentication(req *http.Request, authType string, authConfig map[string]string, onRefreshToken func(string), tlsConfig *ResolvedTLSConfig) error {
 	switch strings.ToLower(authType) {
 	case "basic":
 		username := authConfig["username"]
@@ -147,7 +272,7 @@ func (r *RESTClient) addAuthentication(req *http.Request, authType string, authC
 		if username != "" || password != "" {
 			req.SetBasicAuth(username, password)
 		}
-	case "bearer":
+	case "bearer", "serviceaccounttoken":
 		token := authConfig["token"]
 		if token != "" {
 			req.Header.Set("Authorization", "Bearer "+token)
@@ -162,14 +287,27 @@ func (r *RESTClient) addAuthentication(req *http.Request, authType string, authC
 			req.Header.Set(header, apiKey)
 		}
 	case "oauth2":
-		// For OAuth2, we need to get a token using client credentials flow
-		token, err := r.getOAuth2Token(req.Context(), authConfig)
+		// For OAuth2, we need to get a token from the token endpoint using the configured grant
+		token, err := r.getOAuth2Token(req.Context(), authConfig, onRefreshToken, tlsConfig)
 		if err != nil {
 			return fmt.Errorf("failed to get OAuth2 token: %w", err)
 		}
 		if token != "" {
 			req.Header.Set("Authorization", "Bearer "+token)
 		}
+	case "awssigv4":
+		credentials := AWSCredentials{
+			AccessKeyID:     authConfig["accessKeyId"],
+			SecretAccessKey: authConfig["secretAccessKey"],
+			SessionToken:    authConfig["sessionToken"],
+		}
+		if err := signAWSV4(req, credentials, authConfig["region"], authConfig["service"], time.Now()); err != nil {
+			return fmt.Errorf("failed to sign request: %w", err)
+		}
+	case "hmac":
+		if err := signHMAC(req, authConfig, time.Now()); err != nil {
+			return fmt.Errorf("failed to sign request: %w", err)
+		}
 	case "":
 		// No authentication
 	default:
@@ -178,36 +316,16 @@ func (r *RESTClient) addAuthentication(req *http.Request, authType string, authC
 	return nil
 }
 
-// getOAuth2Token performs OAuth2 client credentials flow to get an access token.
-func (r *RESTClient) getOAuth2Token(ctx context.Context, authConfig map[string]string) (string, error) {
-	clientID := authConfig["clientId"]
-	clientSecret := authConfig["clientSecret"]
-	tokenURL := authConfig["tokenUrl"]
-	scopes := authConfig["scopes"]
-
-	if clientID == "" || clientSecret == "" || tokenURL == "" {
-		return "", fmt.Errorf("OAuth2 requires clientId, clientSecret, and tokenUrl")
-	}
-
-	// Configure OAuth2 client credentials
-	config := &clientcredentials.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		TokenURL:     tokenURL,
-	}
-
-	// Parse scopes if provided
-	if scopes != "" {
-		config.Scopes = strings.Fields(scopes)
-	}
-
-	// Get token
-	token, err := config.Token(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to retrieve OAuth2 token: %w", err)
+// extractItems applies the transform of the request to a response and extracts its items.
+func (r *RESTClient) extractItems(ctx context.Context, body []byte, config HTTPConfig) ([]ItemResult, error) {
+	if config.Transform != "" {
+		transformed, err := TransformResponse(ctx, body, config.Transform)
+		if err != nil {
+			return nil, err
+		}
+		body = transformed
 	}
-
-	return token.AccessToken, nil
+	return r.parseResponse(body, config.ResponsePath)
 }
 
 // parseResponse extracts items from the HTTP response.
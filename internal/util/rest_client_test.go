@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -195,7 +196,7 @@ func TestRESTClient_addAuthentication(t *testing.T) {
 			req, err := http.NewRequest("GET", "http://example.com", nil)
 			require.NoError(t, err)
 
-			err = client.addAuthentication(req, tt.authType, tt.authConfig)
+			err = client.addAuthentication(req, tt.authType, tt.authConfig, nil, nil)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -303,6 +304,92 @@ func TestRESTClient_Execute_Error_Cases(t *testing.T) {
 	}
 }
 
+func TestRESTClient_Execute_Limits(t *testing.T) {
+	body := `[{"id": 1, "name": "Alice"}, {"id": 2, "name": "Bob"}]`
+
+	tests := []struct {
+		name        string
+		options     RESTClientOptions
+		config      HTTPConfig
+		delay       time.Duration
+		chunked     bool
+		status      int
+		errContains string
+	}{
+		{
+			name:    "response within limit",
+			options: RESTClientOptions{MaxResponseBytes: int64(len(body))},
+		},
+		{
+			name:        "response exceeds default limit",
+			options:     RESTClientOptions{MaxResponseBytes: 16},
+			errContains: "exceeds the maximum size of 16 bytes",
+		},
+		{
+			name:        "chunked response exceeds limit",
+			options:     RESTClientOptions{MaxResponseBytes: 16},
+			chunked:     true,
+			errContains: "exceeds the maximum size of 16 bytes",
+		},
+		{
+			name:        "per-request limit overrides default",
+			config:      HTTPConfig{MaxResponseBytes: 8},
+			errContains: "exceeds the maximum size of 8 bytes",
+		},
+		{
+			name:        "error response is truncated",
+			options:     RESTClientOptions{MaxResponseBytes: 8},
+			status:      http.StatusBadGateway,
+			errContains: "status 502: [{\"id\": ",
+		},
+		{
+			name:        "request exceeds default timeout",
+			options:     RESTClientOptions{Timeout: 50 * time.Millisecond},
+			delay:       500 * time.Millisecond,
+			errContains: "deadline exceeded",
+		},
+		{
+			name:        "per-request timeout overrides default",
+			config:      HTTPConfig{Timeout: 50 * time.Millisecond},
+			delay:       500 * time.Millisecond,
+			errContains: "deadline exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-time.After(tt.delay):
+				case <-r.Context().Done():
+					return
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				if tt.chunked {
+					w.(http.Flusher).Flush()
+				}
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			client := NewRESTClientWithOptions(tt.options)
+			tt.config.URL = server.URL
+			tt.config.Method = "GET"
+
+			items, err := client.Execute(context.Background(), tt.config)
+			if tt.errContains == "" {
+				require.NoError(t, err)
+				assert.Len(t, items, 2)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
 func TestRESTClient_ExecuteStatusUpdate(t *testing.T) {
 	// Create a test server
 	receivedBody := ""
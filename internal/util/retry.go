@@ -0,0 +1,221 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	DefaultRetryMaxAttempts   = 3
+	DefaultRetryBaseBackoff   = time.Second
+	DefaultRetryMaxBackoff    = 30 * time.Second
+	DefaultRetryJitterPercent = 20
+)
+
+// DefaultRetryableStatusCodes are the status codes retried when none are configured.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryConfig represents the retry policy for HTTP requests.
+type RetryConfig struct {
+	MaxAttempts          int
+	BaseBackoff          time.Duration
+	MaxBackoff           time.Duration
+	JitterPercent        *int
+	RetryableStatusCodes []int
+	// OnAttempt, when set, is called after every attempt.
+	OnAttempt func(attempt RetryAttempt)
+}
+
+// RetryAttempt describes the outcome of a single attempt of an HTTP request.
+type RetryAttempt struct {
+	// Attempt is the 1-based attempt number.
+	Attempt int
+	// StatusCode is the response status code, or 0 if the request failed without a response.
+	StatusCode int
+	// Err is the transport error, if any.
+	Err error
+	// Backoff is the wait before the next attempt, or 0 if no retry follows.
+	Backoff time.Duration
+}
+
+// Reason returns a short human-readable description of the attempt outcome.
+func (a RetryAttempt) Reason() string {
+	if a.Err != nil {
+		return a.Err.Error()
+	}
+	return fmt.Sprintf("status %d", a.StatusCode)
+}
+
+// do sends the request built by newRequest, retrying according to the policy.
+// The request is rebuilt for every attempt so that bodies and credentials are fresh,
+// and every attempt, including reading the response body, is bounded by timeout.
+// The caller is responsible for closing the body of the returned response.
+func (r *RESTClient) do(ctx context.Context, httpClient *http.Client, retry *RetryConfig, timeout time.Duration, newRequest func(ctx context.Context) (*http.Request, error)) (*http.Response, error) {
+	logger := log.FromContext(ctx)
+
+	maxAttempts := 1
+	if retry != nil {
+		maxAttempts = retry.maxAttempts()
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		req, err := newRequest(attemptCtx)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		resp, err := httpClient.Do(req)
+		result := RetryAttempt{Attempt: attempt, Err: err}
+		if resp != nil {
+			result.StatusCode = resp.StatusCode
+			// Keep the attempt context alive until the caller has read the body
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		} else {
+			cancel()
+		}
+
+		if err == nil && (retry == nil || !retry.isRetryable(resp.StatusCode)) {
+			retry.notify(result)
+			return resp, nil
+		}
+
+		// Give up when the attempts are exhausted or the context is done
+		if attempt >= maxAttempts || ctx.Err() != nil {
+			retry.notify(result)
+			if err != nil {
+				if attempt > 1 {
+					err = fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+				}
+				return nil, err
+			}
+			return resp, nil
+		}
+
+		backoff := retry.backoff(attempt)
+		if resp != nil {
+			if wait, ok := retryAfter(resp); ok {
+				if wait > retry.maxBackoff() {
+					retry.notify(result)
+					logger.Info("Not retrying HTTP request, Retry-After exceeds the maximum backoff",
+						"url", req.URL.String(), "attempt", attempt, "retryAfter", wait)
+					return resp, nil
+				}
+				backoff = wait
+			}
+			// Discard the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		result.Backoff = backoff
+		retry.notify(result)
+		logger.Info("Retrying HTTP request", "url", req.URL.String(), "attempt", attempt,
+			"maxAttempts", maxAttempts, "reason", result.Reason(), "backoff", backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("HTTP request aborted after %d attempt(s): %w", attempt, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// cancelOnClose cancels the attempt context when the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	defer c.cancel()
+	return c.ReadCloser.Close()
+}
+
+func (c *RetryConfig) maxAttempts() int {
+	if c.MaxAttempts <= 0 {
+		return DefaultRetryMaxAttempts
+	}
+	return c.MaxAttempts
+}
+
+func (c *RetryConfig) maxBackoff() time.Duration {
+	if c.MaxBackoff <= 0 {
+		return DefaultRetryMaxBackoff
+	}
+	return c.MaxBackoff
+}
+
+// isRetryable reports whether a response with the given status code should be retried.
+func (c *RetryConfig) isRetryable(statusCode int) bool {
+	codes := c.RetryableStatusCodes
+	if len(codes) == 0 {
+		codes = DefaultRetryableStatusCodes
+	}
+	return slices.Contains(codes, statusCode)
+}
+
+// backoff returns the exponential backoff with jitter before the given attempt is retried.
+func (c *RetryConfig) backoff(attempt int) time.Duration {
+	base := c.BaseBackoff
+	if base <= 0 {
+		base = DefaultRetryBaseBackoff
+	}
+	maxBackoff := c.maxBackoff()
+
+	backoff := base
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+
+	jitterPercent := DefaultRetryJitterPercent
+	if c.JitterPercent != nil {
+		jitterPercent = *c.JitterPercent
+	}
+	if jitterPercent > 0 {
+		backoff += time.Duration(rand.Int64N(int64(backoff)*int64(jitterPercent)/100 + 1))
+	}
+
+	return min(backoff, maxBackoff)
+}
+
+// notify reports an attempt to the OnAttempt hook, if any.
+func (c *RetryConfig) notify(attempt RetryAttempt) {
+	if c != nil && c.OnAttempt != nil {
+		c.OnAttempt(attempt)
+	}
+}
+
+// retryAfter parses the Retry-After header of 429 and 503 responses.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
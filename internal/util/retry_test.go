@@ -0,0 +1,204 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTClient_Execute_Retry(t *testing.T) {
+	client := NewRESTClient()
+	noJitter := 0
+
+	t.Run("retries retryable status codes until success", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.Write([]byte(`[{"id": 1}]`))
+		}))
+		defer server.Close()
+
+		var attempts []RetryAttempt
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL: server.URL,
+			Retry: &RetryConfig{
+				MaxAttempts:   3,
+				BaseBackoff:   time.Millisecond,
+				JitterPercent: &noJitter,
+				OnAttempt:     func(a RetryAttempt) { attempts = append(attempts, a) },
+			},
+		})
+		require.NoError(t, err)
+		assert.Len(t, items, 1)
+		assert.Equal(t, int32(3), calls.Load())
+		require.Len(t, attempts, 3)
+		assert.Equal(t, http.StatusBadGateway, attempts[0].StatusCode)
+		assert.Equal(t, time.Millisecond, attempts[0].Backoff)
+		assert.Equal(t, 2*time.Millisecond, attempts[1].Backoff)
+		assert.Equal(t, http.StatusOK, attempts[2].StatusCode)
+		assert.Zero(t, attempts[2].Backoff)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL:   server.URL,
+			Retry: &RetryConfig{MaxAttempts: 2, BaseBackoff: time.Millisecond},
+		})
+		assert.Error(t, err)
+		assert.Nil(t, items)
+		assert.Contains(t, err.Error(), "status 503")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("does not retry non-retryable status codes", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		_, err := client.Execute(context.Background(), HTTPConfig{
+			URL:   server.URL,
+			Retry: &RetryConfig{MaxAttempts: 3, BaseBackoff: time.Millisecond},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("custom retryable status codes", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		_, err := client.Execute(context.Background(), HTTPConfig{
+			URL: server.URL,
+			Retry: &RetryConfig{
+				MaxAttempts:          2,
+				BaseBackoff:          time.Millisecond,
+				RetryableStatusCodes: []int{http.StatusInternalServerError},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("honours Retry-After on 429", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`[]`))
+		}))
+		defer server.Close()
+
+		var attempts []RetryAttempt
+		_, err := client.Execute(context.Background(), HTTPConfig{
+			URL: server.URL,
+			Retry: &RetryConfig{
+				BaseBackoff: time.Millisecond,
+				OnAttempt:   func(a RetryAttempt) { attempts = append(attempts, a) },
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, attempts, 2)
+		assert.Equal(t, time.Second, attempts[0].Backoff)
+	})
+
+	t.Run("stops when Retry-After exceeds max backoff", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		_, err := client.Execute(context.Background(), HTTPConfig{
+			URL:   server.URL,
+			Retry: &RetryConfig{MaxAttempts: 5, MaxBackoff: time.Second},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("retries status update callbacks", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		err := client.ExecuteStatusUpdate(context.Background(), HTTPStatusUpdateConfig{
+			URL:          server.URL,
+			Method:       "POST",
+			BodyTemplate: `{}`,
+			Retry:        &RetryConfig{BaseBackoff: time.Millisecond},
+		}, map[string]interface{}{})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestRetryConfig_backoff(t *testing.T) {
+	noJitter := 0
+	config := &RetryConfig{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second, JitterPercent: &noJitter}
+	assert.Equal(t, time.Second, config.backoff(1))
+	assert.Equal(t, 2*time.Second, config.backoff(2))
+	assert.Equal(t, 4*time.Second, config.backoff(3))
+	assert.Equal(t, 5*time.Second, config.backoff(4))
+
+	jitter := 50
+	config.JitterPercent = &jitter
+	for range 20 {
+		backoff := config.backoff(1)
+		assert.GreaterOrEqual(t, backoff, time.Second)
+		assert.LessOrEqual(t, backoff, 1500*time.Millisecond)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	resp.Header.Set("Retry-After", "7")
+	wait, ok := retryAfter(resp)
+	assert.True(t, ok)
+	assert.Equal(t, 7*time.Second, wait)
+
+	resp.Header.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	wait, ok = retryAfter(resp)
+	assert.True(t, ok)
+	assert.Greater(t, wait, 59*time.Minute)
+
+	resp.StatusCode = http.StatusBadGateway
+	_, ok = retryAfter(resp)
+	assert.False(t, ok, "Retry-After is only honoured for 429 and 503")
+}
@@ -0,0 +1,144 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultServiceAccountTokenExpirationSeconds is the requested lifetime of minted tokens
+	DefaultServiceAccountTokenExpirationSeconds = 3600
+	// serviceAccountTokenRefreshRatio is the fraction of the lifetime after which a token is minted again
+	serviceAccountTokenRefreshRatio = 0.8
+)
+
+// apiServerAudiences are the well-known audiences of the Kubernetes API server. Tokens for these
+// audiences authenticate against the API server, so they are never sent to other endpoints.
+var apiServerAudiences = []string{
+	"api",
+	"kubernetes",
+	"kubernetes.default",
+	"kubernetes.default.svc",
+	"kubernetes.default.svc.cluster.local",
+	"https://kubernetes.default",
+	"https://kubernetes.default.svc",
+	"https://kubernetes.default.svc.cluster.local",
+}
+
+// serviceAccountTokenKey identifies a minted ServiceAccount token
+type serviceAccountTokenKey struct {
+	Namespace         string
+	Name              string
+	Audience          string
+	ExpirationSeconds int64
+}
+
+// serviceAccountToken is a minted token with the time it should be replaced
+type serviceAccountToken struct {
+	token     string
+	refreshAt time.Time
+	expiresAt time.Time
+}
+
+// serviceAccountTokenCache caches minted ServiceAccount tokens until they are near expiry.
+// It is safe for concurrent use.
+type serviceAccountTokenCache struct {
+	mu     sync.Mutex
+	tokens map[serviceAccountTokenKey]*serviceAccountToken
+}
+
+// resolveServiceAccountToken returns the authentication configuration for a ServiceAccount token
+// minted with the TokenRequest API. The ServiceAccount must be in the namespace of the resource,
+// and the token must be minted for an audience other than the API server.
+func (ar *AuthResolver) resolveServiceAccountToken(ctx context.Context, namespace string, authRef *httpv1alpha1.HTTPAuthenticationRef) (*ResolvedAuthConfig, error) {
+	if authRef.Namespace != "" && authRef.Namespace != namespace {
+		return nil, fmt.Errorf("ServiceAccount token authentication requires the ServiceAccount '%s' to be in namespace '%s'", authRef.Name, namespace)
+	}
+	if authRef.Audience == "" {
+		return nil, fmt.Errorf("ServiceAccount token authentication requires an audience")
+	}
+	audience := strings.TrimSuffix(strings.ToLower(authRef.Audience), "/")
+	if slices.Contains(apiServerAudiences, audience) {
+		return nil, fmt.Errorf("ServiceAccount token authentication does not allow the API server audience '%s'", authRef.Audience)
+	}
+
+	expirationSeconds := int64(DefaultServiceAccountTokenExpirationSeconds)
+	if authRef.ExpirationSeconds != nil {
+		expirationSeconds = *authRef.ExpirationSeconds
+	}
+	key := serviceAccountTokenKey{
+		Namespace:         namespace,
+		Name:              authRef.Name,
+		Audience:          authRef.Audience,
+		ExpirationSeconds: expirationSeconds,
+	}
+
+	token, err := ar.serviceAccountToken(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &ResolvedAuthConfig{
+		AuthType:   authRef.Type,
+		AuthConfig: map[string]string{"token": token},
+	}, nil
+}
+
+// serviceAccountToken returns a cached token for key, minting a new one when it is missing or near expiry
+func (ar *AuthResolver) serviceAccountToken(ctx context.Context, key serviceAccountTokenKey) (string, error) {
+	cache := &ar.saTokens
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	now := ar.currentTime()
+	if cache.tokens == nil {
+		cache.tokens = make(map[serviceAccountTokenKey]*serviceAccountToken)
+	}
+	for k, cached := range cache.tokens {
+		if !now.Before(cached.expiresAt) {
+			delete(cache.tokens, k)
+		}
+	}
+	if cached, ok := cache.tokens[key]; ok && now.Before(cached.refreshAt) {
+		return cached.token, nil
+	}
+
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences:         []string{key.Audience},
+			ExpirationSeconds: &key.ExpirationSeconds,
+		},
+	}
+	if err := ar.Client.SubResource("token").Create(ctx, serviceAccount, tokenRequest); err != nil {
+		return "", fmt.Errorf("failed to request token for ServiceAccount '%s' in namespace '%s': %w", key.Name, key.Namespace, err)
+	}
+
+	// The API server may shorten the lifetime, so refresh relative to the granted expiry
+	expiresAt := tokenRequest.Status.ExpirationTimestamp.Time
+	lifetime := expiresAt.Sub(now)
+	cache.tokens[key] = &serviceAccountToken{
+		token:     tokenRequest.Status.Token,
+		refreshAt: now.Add(time.Duration(float64(lifetime) * serviceAccountTokenRefreshRatio)),
+		expiresAt: expiresAt,
+	}
+	ar.Log.V(1).Info("Minted ServiceAccount token", "serviceAccount", key.Name, "namespace", key.Namespace, "expiresAt", expiresAt)
+	return tokenRequest.Status.Token, nil
+}
+
+// currentTime returns the current time, which is overridden in tests
+func (ar *AuthResolver) currentTime() time.Time {
+	if ar.now != nil {
+		return ar.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,101 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+func TestAuthResolver_ServiceAccountToken(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var requests []*authenticationv1.TokenRequest
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "poller", Namespace: "team-a"}}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				tokenRequest := subResource.(*authenticationv1.TokenRequest)
+				requests = append(requests, tokenRequest.DeepCopy())
+				tokenRequest.Status.Token = fmt.Sprintf("token-%d", len(requests))
+				tokenRequest.Status.ExpirationTimestamp = metav1.NewTime(now.Add(time.Duration(*tokenRequest.Spec.ExpirationSeconds) * time.Second))
+				return nil
+			},
+		}).
+		Build()
+
+	resolver := NewAuthResolver(fakeClient, logr.Discard())
+	resolver.now = func() time.Time { return now }
+
+	authRef := &httpv1alpha1.HTTPAuthenticationRef{
+		Name:     "poller",
+		Type:     "serviceAccountToken",
+		Audience: "https://inventory.team-b.svc",
+	}
+
+	config, err := resolver.ResolveAuthenticationConfig(context.Background(), "team-a", authRef)
+	require.NoError(t, err)
+	assert.Equal(t, &ResolvedAuthConfig{AuthType: "serviceAccountToken", AuthConfig: map[string]string{"token": "token-1"}}, config)
+	require.Len(t, requests, 1)
+	assert.Equal(t, []string{"https://inventory.team-b.svc"}, requests[0].Spec.Audiences)
+	assert.Equal(t, int64(DefaultServiceAccountTokenExpirationSeconds), *requests[0].Spec.ExpirationSeconds)
+
+	t.Run("cached until near expiry", func(t *testing.T) {
+		now = now.Add(47 * time.Minute)
+		config, err := resolver.ResolveAuthenticationConfig(context.Background(), "team-a", authRef)
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", config.AuthConfig["token"])
+		assert.Len(t, requests, 1)
+
+		now = now.Add(2 * time.Minute)
+		config, err = resolver.ResolveAuthenticationConfig(context.Background(), "team-a", authRef)
+		require.NoError(t, err)
+		assert.Equal(t, "token-2", config.AuthConfig["token"])
+		assert.Len(t, requests, 2)
+	})
+
+	t.Run("other namespace is rejected", func(t *testing.T) {
+		_, err := resolver.ResolveAuthenticationConfig(context.Background(), "team-a", &httpv1alpha1.HTTPAuthenticationRef{
+			Name:      "poller",
+			Namespace: "kube-system",
+			Type:      "serviceAccountToken",
+		})
+		require.Error(t, err)
+		assert.Len(t, requests, 2)
+	})
+
+	t.Run("audience is required", func(t *testing.T) {
+		_, err := resolver.ResolveAuthenticationConfig(context.Background(), "team-a", &httpv1alpha1.HTTPAuthenticationRef{
+			Name: "poller",
+			Type: "serviceAccountToken",
+		})
+		assert.ErrorContains(t, err, "requires an audience")
+		assert.Len(t, requests, 2)
+	})
+
+	t.Run("API server audience is rejected", func(t *testing.T) {
+		_, err := resolver.ResolveAuthenticationConfig(context.Background(), "team-a", &httpv1alpha1.HTTPAuthenticationRef{
+			Name:     "poller",
+			Type:     "serviceAccountToken",
+			Audience: "https://Kubernetes.default.svc/",
+		})
+		assert.ErrorContains(t, err, "does not allow the API server audience")
+		assert.Len(t, requests, 2)
+	})
+}
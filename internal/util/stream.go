@@ -0,0 +1,307 @@
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Supported stream formats
+const (
+	StreamFormatSSE    = "sse"
+	StreamFormatNDJSON = "ndjson"
+
+	// DefaultStreamTypeField is the path of the event type in NDJSON events
+	DefaultStreamTypeField = "type"
+	// DefaultStreamDeleteType is the event type that deletes an item
+	DefaultStreamDeleteType = "delete"
+)
+
+// StreamConfig configures a streaming request.
+type StreamConfig struct {
+	// Format of the stream: sse or ndjson. Defaults to sse.
+	Format string
+	// OnConnect, when set, is called after every successful connection
+	OnConnect func()
+	// OnDisconnect, when set, is called with the reason of every lost connection
+	OnDisconnect func(err error)
+}
+
+// StreamEvent is a single event of a stream.
+type StreamEvent struct {
+	// ID of the event, sent as Last-Event-ID when reconnecting. Only set for SSE.
+	ID string
+	// Type of the event. For SSE, the event field, defaulting to "message". Empty for NDJSON.
+	Type string
+	Data []byte
+}
+
+// Stream holds a long-lived connection to a Server-Sent Events or NDJSON stream and calls onEvent
+// for every event, until the context is cancelled. Lost connections are re-established with
+// the backoff of config.Retry, or the reconnection time sent by the server. SSE streams are
+// resumed with the Last-Event-ID header. config.Timeout only bounds establishing the connection.
+func (r *RESTClient) Stream(ctx context.Context, config HTTPConfig, onEvent func(StreamEvent)) error {
+	if config.Pagination != nil || config.GraphQL != nil || config.ItemDetail != nil {
+		return fmt.Errorf("streams cannot be combined with pagination, GraphQL queries or item details")
+	}
+	streamConfig := config.Stream
+	if streamConfig == nil {
+		streamConfig = &StreamConfig{}
+	}
+	if streamConfig.Format != "" && streamConfig.Format != StreamFormatSSE && streamConfig.Format != StreamFormatNDJSON {
+		return fmt.Errorf("unsupported stream format '%s'", streamConfig.Format)
+	}
+	retry := config.Retry
+	if retry == nil {
+		retry = &RetryConfig{}
+	}
+	logger := log.FromContext(ctx)
+
+	state := &streamState{}
+	for attempt := 1; ; attempt++ {
+		connected, err := r.streamOnce(ctx, config, streamConfig, state, onEvent)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if streamConfig.OnDisconnect != nil {
+			streamConfig.OnDisconnect(err)
+		}
+		if connected {
+			attempt = 1
+		}
+		backoff := retry.backoff(attempt)
+		if state.retry > 0 && attempt == 1 {
+			backoff = state.retry
+		}
+		logger.Info("Stream disconnected, reconnecting", "url", config.URL, "reason", err.Error(), "backoff", backoff)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// streamState is kept across the connections of a stream
+type streamState struct {
+	lastEventID string
+	// retry is the reconnection time sent by an SSE server
+	retry time.Duration
+}
+
+// streamOnce connects to the stream and reads events until the connection is lost.
+// It reports whether the connection was established.
+func (r *RESTClient) streamOnce(ctx context.Context, config HTTPConfig, streamConfig *StreamConfig, state *streamState, onEvent func(StreamEvent)) (bool, error) {
+	httpClient, err := r.httpClientFor(config.TLS)
+	if err != nil {
+		return false, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+
+	headers := maps.Clone(config.Headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	if _, ok := headers["Accept"]; !ok {
+		if streamConfig.Format == StreamFormatNDJSON {
+			headers["Accept"] = "application/x-ndjson"
+		} else {
+			headers["Accept"] = "text/event-stream"
+		}
+	}
+	if state.lastEventID != "" {
+		headers["Last-Event-ID"] = state.lastEventID
+	}
+
+	connCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	req, err := r.buildRequest(connCtx, config.URL, config.Method, headers, config.Body, config.AuthType, config.AuthConfig, config.OnRefreshToken, config.TLS)
+	if err != nil {
+		return false, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	// The timeout only applies until the response headers are received
+	connectTimer := time.AfterFunc(r.timeout(config.Timeout), cancel)
+	resp, err := httpClient.Do(req)
+	if !connectTimer.Stop() && err == nil {
+		resp.Body.Close()
+		return false, fmt.Errorf("timed out connecting to stream")
+	}
+	if err != nil {
+		return false, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	maxBytes := r.maxResponseBytes(config.MaxResponseBytes)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("HTTP request failed with status %d: %s", resp.StatusCode, readErrorBody(resp, maxBytes))
+	}
+	if streamConfig.OnConnect != nil {
+		streamConfig.OnConnect()
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), int(maxBytes))
+	if streamConfig.Format == StreamFormatNDJSON {
+		err = readNDJSONStream(scanner, onEvent)
+	} else {
+		err = readSSEStream(scanner, state, onEvent)
+	}
+	if err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return true, fmt.Errorf("stream event exceeds the maximum size of %d bytes", maxBytes)
+		}
+		return true, fmt.Errorf("failed to read stream: %w", err)
+	}
+	return true, fmt.Errorf("stream closed by server")
+}
+
+// readNDJSONStream reads an event from every non-empty line
+func readNDJSONStream(scanner *bufio.Scanner, onEvent func(StreamEvent)) error {
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		onEvent(StreamEvent{Data: bytes.Clone(line)})
+	}
+	return scanner.Err()
+}
+
+// readSSEStream reads Server-Sent Events, dispatching an event at every blank line.
+// See https://html.spec.whatwg.org/multipage/server-sent-events.html#event-stream-interpretation
+func readSSEStream(scanner *bufio.Scanner, state *streamState, onEvent func(StreamEvent)) error {
+	var eventType string
+	var data []string
+	hasData := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if hasData {
+				onEvent(StreamEvent{ID: state.lastEventID, Type: defaultString(eventType, "message"), Data: []byte(strings.Join(data, "\n"))})
+			}
+			eventType, data, hasData = "", nil, false
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// Comment, e.g. a keep-alive
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "id":
+			if !strings.Contains(value, "\x00") {
+				state.lastEventID = value
+			}
+		case "retry":
+			if millis, err := strconv.Atoi(value); err == nil && millis >= 0 {
+				state.retry = time.Duration(millis) * time.Millisecond
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// StreamItemSet folds the events of a stream into a set of items keyed by their ID.
+type StreamItemSet struct {
+	// KeyField is the gjson path to the ID of an item
+	KeyField string
+	// TypeField is the gjson path to the type of an event in its data. When the data has no type,
+	// the type of the event is used.
+	TypeField string
+	// DataPath is the gjson path to the item in the data of an event. Defaults to the data itself.
+	DataPath string
+	// DeleteTypes are the event types that delete an item. Defaults to ["delete"].
+	DeleteTypes []string
+	// SnapshotTypes are the event types marking that the current state of every item has been sent.
+	// Their data is not an item.
+	SnapshotTypes []string
+
+	items map[string]ItemResult
+	// complete is set by the first snapshot event
+	complete bool
+}
+
+// Apply inserts, updates or deletes the item of the event, and reports whether the set changed.
+// The first snapshot event completes the set, which is reported as a change.
+func (s *StreamItemSet) Apply(event StreamEvent) (bool, error) {
+	if s.items == nil {
+		s.items = make(map[string]ItemResult)
+	}
+
+	eventType := event.Type
+	if s.TypeField != "" {
+		if result := gjson.GetBytes(event.Data, s.TypeField); result.Exists() {
+			eventType = result.String()
+		}
+	}
+	if slices.Contains(s.SnapshotTypes, eventType) {
+		changed := !s.complete
+		s.complete = true
+		return changed, nil
+	}
+
+	data := event.Data
+	if s.DataPath != "" {
+		result := gjson.GetBytes(event.Data, s.DataPath)
+		if !result.Exists() {
+			return false, fmt.Errorf("data path '%s' not found in event", s.DataPath)
+		}
+		data = []byte(result.Raw)
+	}
+	var item ItemResult
+	if err := json.Unmarshal(data, &item); err != nil || item == nil {
+		return false, fmt.Errorf("event data is not a JSON object")
+	}
+	key, ok, err := itemKey(item, s.KeyField)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return false, fmt.Errorf("key '%s' not found in event", s.KeyField)
+	}
+
+	deleteTypes := s.DeleteTypes
+	if len(deleteTypes) == 0 {
+		deleteTypes = []string{DefaultStreamDeleteType}
+	}
+	existing, exists := s.items[key]
+	if slices.Contains(deleteTypes, eventType) {
+		delete(s.items, key)
+		return exists, nil
+	}
+	s.items[key] = item
+	return !exists || !reflect.DeepEqual(existing, item), nil
+}
+
+// Complete reports whether a snapshot event confirmed that the set holds every item.
+func (s *StreamItemSet) Complete() bool {
+	return s.complete
+}
+
+// Items returns the items of the set, ordered by their ID.
+func (s *StreamItemSet) Items() []ItemResult {
+	items := make([]ItemResult, 0, len(s.items))
+	for _, key := range slices.Sorted(maps.Keys(s.items)) {
+		items = append(items, s.items[key])
+	}
+	return items
+}
@@ -0,0 +1,175 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRESTClient_Stream_SSE(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "text/event-stream", r.Header.Get("Accept"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		switch connections.Add(1) {
+		case 1:
+			assert.Empty(t, r.Header.Get("Last-Event-ID"))
+			fmt.Fprint(w, "retry: 10\n: keep-alive\n\nid: 1\nevent: upsert\ndata: {\"id\": \"a\",\ndata: \"name\": \"alice\"}\n\nid: 2\ndata: {\"id\": \"b\"}\n\n")
+		default:
+			// Resume after the last event received
+			assert.Equal(t, "2", r.Header.Get("Last-Event-ID"))
+			fmt.Fprint(w, "id: 3\nevent: delete\ndata: {\"id\": \"a\"}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var events []StreamEvent
+	var disconnects int
+	err := NewRESTClient().Stream(ctx, HTTPConfig{
+		URL:        server.URL,
+		AuthType:   "bearer",
+		AuthConfig: map[string]string{"token": "token"},
+		Stream:     &StreamConfig{OnDisconnect: func(error) { disconnects++ }},
+	}, func(e StreamEvent) {
+		events = append(events, e)
+		if len(events) == 3 {
+			cancel()
+		}
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, StreamEvent{ID: "1", Type: "upsert", Data: []byte("{\"id\": \"a\",\n\"name\": \"alice\"}")}, events[0])
+	assert.Equal(t, StreamEvent{ID: "2", Type: "message", Data: []byte(`{"id": "b"}`)}, events[1])
+	assert.Equal(t, StreamEvent{ID: "3", Type: "delete", Data: []byte(`{"id": "a"}`)}, events[2])
+	assert.Equal(t, 1, disconnects)
+}
+
+func TestRESTClient_Stream_NDJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Accept"))
+		fmt.Fprint(w, "{\"type\": \"upsert\", \"id\": 1}\n\n{\"type\": \"delete\", \"id\": 1}\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	connected := false
+	var events []StreamEvent
+	err := NewRESTClient().Stream(ctx, HTTPConfig{
+		URL:    server.URL,
+		Stream: &StreamConfig{Format: StreamFormatNDJSON, OnConnect: func() { connected = true }},
+	}, func(e StreamEvent) {
+		events = append(events, e)
+		if len(events) == 2 {
+			cancel()
+		}
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, connected)
+	assert.Equal(t, []StreamEvent{
+		{Data: []byte(`{"type": "upsert", "id": 1}`)},
+		{Data: []byte(`{"type": "delete", "id": 1}`)},
+	}, events)
+}
+
+func TestRESTClient_Stream_Reconnect(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if connections.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "data: {\"id\": 1}\n\n")
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	jitter := 0
+	var reasons []string
+	err := NewRESTClient().Stream(ctx, HTTPConfig{
+		URL:    server.URL,
+		Retry:  &RetryConfig{BaseBackoff: time.Millisecond, JitterPercent: &jitter},
+		Stream: &StreamConfig{OnDisconnect: func(err error) { reasons = append(reasons, err.Error()) }},
+	}, func(StreamEvent) { cancel() })
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, reasons, 2)
+	assert.Contains(t, reasons[0], "status 503")
+}
+
+func TestRESTClient_Stream_Unsupported(t *testing.T) {
+	err := NewRESTClient().Stream(context.Background(), HTTPConfig{URL: "http://example.com", Pagination: &PaginationConfig{}}, func(StreamEvent) {})
+	assert.Error(t, err)
+}
+
+func TestStreamItemSet_Apply(t *testing.T) {
+	set := &StreamItemSet{KeyField: "id", TypeField: "op", DataPath: "object", DeleteTypes: []string{"removed"}}
+
+	apply := func(data string) bool {
+		changed, err := set.Apply(StreamEvent{Type: "message", Data: []byte(data)})
+		require.NoError(t, err)
+		return changed
+	}
+	assert.True(t, apply(`{"op": "added", "object": {"id": 2, "name": "bob"}}`))
+	assert.True(t, apply(`{"op": "added", "object": {"id": 1, "name": "alice"}}`))
+	assert.False(t, apply(`{"op": "modified", "object": {"id": 1, "name": "alice"}}`), "unchanged item")
+	assert.True(t, apply(`{"op": "modified", "object": {"id": 1, "name": "alicia"}}`))
+	assert.Equal(t, []ItemResult{
+		{"id": float64(1), "name": "alicia"},
+		{"id": float64(2), "name": "bob"},
+	}, set.Items())
+
+	assert.True(t, apply(`{"op": "removed", "object": {"id": 2}}`))
+	assert.False(t, apply(`{"op": "removed", "object": {"id": 3}}`), "unknown item")
+	assert.Len(t, set.Items(), 1)
+
+	t.Run("event type", func(t *testing.T) {
+		set := &StreamItemSet{KeyField: "id"}
+		_, err := set.Apply(StreamEvent{Type: "upsert", Data: []byte(`{"id": "a"}`)})
+		require.NoError(t, err)
+		changed, err := set.Apply(StreamEvent{Type: "delete", Data: []byte(`{"id": "a"}`)})
+		require.NoError(t, err)
+		assert.True(t, changed)
+		assert.Empty(t, set.Items())
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		set := &StreamItemSet{KeyField: "id", SnapshotTypes: []string{"snapshot"}}
+		_, err := set.Apply(StreamEvent{Type: "upsert", Data: []byte(`{"id": "a"}`)})
+		require.NoError(t, err)
+		assert.False(t, set.Complete())
+
+		changed, err := set.Apply(StreamEvent{Type: "snapshot"})
+		require.NoError(t, err)
+		assert.True(t, changed, "completing the set is a change")
+		assert.True(t, set.Complete())
+		changed, err = set.Apply(StreamEvent{Type: "snapshot"})
+		require.NoError(t, err)
+		assert.False(t, changed)
+		assert.Len(t, set.Items(), 1)
+	})
+
+	t.Run("invalid events", func(t *testing.T) {
+		_, err := set.Apply(StreamEvent{Data: []byte(`{"op": "added"}`)})
+		assert.Error(t, err, "missing data path")
+		_, err = set.Apply(StreamEvent{Data: []byte(`{"object": {"name": "carol"}}`)})
+		assert.Error(t, err, "missing key")
+		_, err = set.Apply(StreamEvent{Data: []byte(`not json`)})
+		assert.Error(t, err)
+	})
+}
@@ -2,18 +2,33 @@ package util
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 )
 
 // TemplateProcessor handles template processing and resource parsing
-type TemplateProcessor struct{}
+type TemplateProcessor struct {
+	// Reader enables the lookup function of resource templates, and configMapValue and
+	// secretValue if AllowValueLookups is set. They fail if Reader is nil.
+	Reader client.Reader
+	// LookupGVKs are the kinds lookup may read, usually the registered GVKs
+	LookupGVKs []schema.GroupVersionKind
+	// AllowValueLookups enables configMapValue, secretValue and lookups of Secrets
+	AllowValueLookups bool
+}
 
 // NewTemplateProcessor creates a new TemplateProcessor
 func NewTemplateProcessor() *TemplateProcessor {
@@ -22,7 +37,12 @@ func NewTemplateProcessor() *TemplateProcessor {
 
 // ProcessTemplate processes a Go template with the given data
 func (tp *TemplateProcessor) ProcessTemplate(templateStr string, data interface{}) (string, error) {
-	tmpl, err := template.New("resource").Funcs(sprig.TxtFuncMap()).Parse(templateStr)
+	return tp.processTemplate(templateStr, data, TemplateInput{})
+}
+
+// processTemplate processes a Go template with the given data, scoping cluster reads to the input
+func (tp *TemplateProcessor) processTemplate(templateStr string, data interface{}, input TemplateInput) (string, error) {
+	tmpl, err := template.New("resource").Funcs(tp.funcMap(input)).Parse(templateStr)
 	if err != nil {
 		return "", fmt.Errorf("failed to parse template: %w", err)
 	}
@@ -66,55 +86,361 @@ func (tp *TemplateProcessor) ParseResources(data string) ([]*unstructured.Unstru
 	return resources, nil
 }
 
+// TemplateInput holds the data exposed to resource templates besides the item itself
+type TemplateInput struct {
+	// Sources holds the items of every additional source by name, exposed as .Sources
+	Sources map[string][]ItemResult
+	// Joined holds the matched source items by source name for every item, exposed as .Joined
+	Joined []map[string][]ItemResult
+	// Response is the JSON body of the response, exposed decoded as .Response to aggregate templates
+	Response []byte
+	// Header holds the response headers, exposed as .Headers to aggregate templates
+	Header http.Header
+	// Namespace scopes the cluster reads of lookup, configMapValue and secretValue.
+	// They fail if it is empty.
+	Namespace string
+	// Context bounds the cluster reads. Defaults to context.Background().
+	Context context.Context
+	// ItemKey is the gjson path to the key of an item. When set, the key is exposed as .ItemKey
+	// and the resources of every item are labeled with it. Items without a unique key fail.
+	ItemKey string
+}
+
 // ProcessHTTPResponseToResources processes HTTP response items into Kubernetes resources
 func (tp *TemplateProcessor) ProcessHTTPResponseToResources(templateStr string, items []ItemResult) ([]*unstructured.Unstructured, error) {
-       var allResources []*unstructured.Unstructured
-       var failedCount int
-       var errorMessages []string
-
-       // Process each item from the HTTP response
-       for i, item := range items {
-	       templateData := map[string]interface{}{
-		       "Item":  item,
-		       "Index": i,
-	       }
-
-	       // Process the template
-	       renderedYAML, err := tp.ProcessTemplate(templateStr, templateData)
-	       if err != nil {
-		       failedCount++
-		       errorMessages = append(errorMessages, fmt.Sprintf("item %d: template error: %v", i, err))
-		       continue
-	       }
-
-	       // Parse the generated YAML/JSON into Kubernetes resources
-	       itemResources, err := tp.ParseResources(renderedYAML)
-	       if err != nil {
-		       failedCount++
-		       errorMessages = append(errorMessages, fmt.Sprintf("item %d: parse error: %v", i, err))
-		       continue
-	       }
-
-	       // Add metadata to track the original item data for status updates
-	       itemJSON, _ := json.Marshal(item)
-	       for _, resource := range itemResources {
-		       annotations := resource.GetAnnotations()
-		       if annotations == nil {
-			       annotations = make(map[string]string)
-		       }
-		       annotations["konnektr.io/original-item"] = string(itemJSON)
-		       resource.SetAnnotations(annotations)
-	       }
-
-	       allResources = append(allResources, itemResources...)
-       }
-
-       if len(allResources) == 0 {
-	       return nil, fmt.Errorf("all items failed to process: %v", strings.Join(errorMessages, "; "))
-       }
-       if failedCount > 0 {
-	       // Optionally, log or return a partial error (not fatal)
-	       // return allResources, fmt.Errorf("%d items failed: %v", failedCount, strings.Join(errorMessages, "; "))
-       }
-       return allResources, nil
+	return tp.ProcessItemsToResources(templateStr, items, TemplateInput{})
+}
+
+// ProcessAggregateToResources renders the template once for all items into Kubernetes resources.
+// The template receives the items as .Items, the decoded response as .Response, the response
+// headers as .Headers and the source items as .Sources.
+func (tp *TemplateProcessor) ProcessAggregateToResources(templateStr string, items []ItemResult, input TemplateInput) ([]*unstructured.Unstructured, error) {
+	if items == nil {
+		items = []ItemResult{}
+	}
+	sources := input.Sources
+	if sources == nil {
+		sources = map[string][]ItemResult{}
+	}
+	header := input.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	var response interface{}
+	if len(bytes.TrimSpace(input.Response)) > 0 {
+		if err := json.Unmarshal(input.Response, &response); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	renderedYAML, err := tp.processTemplate(templateStr, map[string]interface{}{
+		"Items":    items,
+		"Response": response,
+		"Headers":  header,
+		"Sources":  sources,
+	}, input)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate template error: %w", err)
+	}
+	resources, err := tp.ParseResources(renderedYAML)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate template parse error: %w", err)
+	}
+	return resources, nil
+}
+
+// TemplateError is returned when one or more items failed to render or parse. The resources of
+// the other items are returned with it.
+type TemplateError struct {
+	Total    int
+	Failures []ItemError
+}
+
+func (e *TemplateError) Error() string {
+	messages := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		messages = append(messages, failure.Error())
+	}
+	if len(e.Failures) == e.Total {
+		return fmt.Sprintf("all items failed to process: %s", strings.Join(messages, "; "))
+	}
+	return fmt.Sprintf("%d of %d items failed to process: %s", len(e.Failures), e.Total, strings.Join(messages, "; "))
+}
+
+// Partial reports whether some items were processed successfully
+func (e *TemplateError) Partial() bool {
+	return len(e.Failures) < e.Total
+}
+
+// Keys returns the keys of the failed items, and whether all of them have a key
+func (e *TemplateError) Keys() ([]string, bool) {
+	keys := make([]string, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		if failure.Key == "" {
+			return nil, false
+		}
+		keys = append(keys, failure.Key)
+	}
+	return keys, true
+}
+
+// Indexes returns the indexes of the failed items
+func (e *TemplateError) Indexes() []int {
+	indexes := make([]int, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		indexes = append(indexes, failure.Index)
+	}
+	return indexes
+}
+
+// ProcessItemsToResources processes items into Kubernetes resources, exposing the input to the template.
+// Items that fail are reported in a *TemplateError, returned alongside the resources of the other items.
+func (tp *TemplateProcessor) ProcessItemsToResources(templateStr string, items []ItemResult, input TemplateInput) ([]*unstructured.Unstructured, error) {
+	var allResources []*unstructured.Unstructured
+	var failures []ItemError
+
+	sources := input.Sources
+	if sources == nil {
+		sources = map[string][]ItemResult{}
+	}
+
+	// Resolve the item keys first, so that items without a unique key are not rendered
+	var keys []string
+	if input.ItemKey != "" {
+		keys, failures = itemKeys(items, input.ItemKey)
+	}
+	failed := make(map[int]bool, len(failures))
+	for _, failure := range failures {
+		failed[failure.Index] = true
+	}
+
+	// Process each item from the HTTP response
+	for i, item := range items {
+		if failed[i] {
+			continue
+		}
+		joined := map[string][]ItemResult{}
+		if i < len(input.Joined) && input.Joined[i] != nil {
+			joined = input.Joined[i]
+		}
+		templateData := map[string]interface{}{
+			"Item":    item,
+			"Index":   i,
+			"Sources": sources,
+			"Joined":  joined,
+		}
+		key := ""
+		if keys != nil {
+			key = keys[i]
+			templateData["ItemKey"] = key
+		}
+
+		// Process the template
+		renderedYAML, err := tp.processTemplate(templateStr, templateData, input)
+		if err != nil {
+			failures = append(failures, ItemError{Index: i, Key: key, Err: fmt.Errorf("template error: %w", err)})
+			continue
+		}
+
+		// Parse the generated YAML/JSON into Kubernetes resources
+		itemResources, err := tp.ParseResources(renderedYAML)
+		if err != nil {
+			failures = append(failures, ItemError{Index: i, Key: key, Err: fmt.Errorf("parse error: %w", err)})
+			continue
+		}
+
+		// Add metadata to track the original item data for status updates
+		itemJSON, _ := json.Marshal(item)
+		for _, resource := range itemResources {
+			annotations := resource.GetAnnotations()
+			if annotations == nil {
+				annotations = make(map[string]string)
+			}
+			annotations["konnektr.io/original-item"] = string(itemJSON)
+			if key != "" {
+				annotations[ItemKeyAnnotation] = key
+				labels := resource.GetLabels()
+				if labels == nil {
+					labels = make(map[string]string)
+				}
+				labels[ItemKeyLabel] = ItemKeyLabelValue(key)
+				resource.SetLabels(labels)
+			}
+			resource.SetAnnotations(annotations)
+		}
+
+		allResources = append(allResources, itemResources...)
+	}
+
+	if len(failures) > 0 {
+		slices.SortFunc(failures, func(a, b ItemError) int { return a.Index - b.Index })
+		return allResources, &TemplateError{Total: len(items), Failures: failures}
+	}
+	return allResources, nil
+}
+
+// funcMap returns the Sprig functions extended with YAML, quoting and cluster read functions.
+// Cluster reads are scoped to the namespace of the input.
+func (tp *TemplateProcessor) funcMap(input TemplateInput) template.FuncMap {
+	funcs := sprig.TxtFuncMap()
+	funcs["toYaml"] = toYAML
+	funcs["fromYaml"] = fromYAML
+	funcs["jsonQuote"] = jsonQuote
+	funcs["jsonEscape"] = jsonEscape
+
+	reader := &templateReader{processor: tp, ctx: input.Context, namespace: input.Namespace}
+	if reader.ctx == nil {
+		reader.ctx = context.Background()
+	}
+	funcs["lookup"] = reader.lookup
+	funcs["configMapValue"] = reader.configMapValue
+	funcs["secretValue"] = reader.secretValue
+	return funcs
+}
+
+// toYAML renders a value as YAML without a trailing newline, to be indented with nindent
+func toYAML(value interface{}) (string, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("toYaml: %w", err)
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// fromYAML parses a YAML or JSON object
+func fromYAML(str string) (map[string]interface{}, error) {
+	result := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(str), &result); err != nil {
+		return nil, fmt.Errorf("fromYaml: %w", err)
+	}
+	return result, nil
+}
+
+// jsonQuote renders a value as a JSON string literal, which is also a valid double-quoted YAML
+// scalar, whatever quotes, backslashes or line breaks it contains. nil renders as "".
+func jsonQuote(value interface{}) (string, error) {
+	var str string
+	if value != nil {
+		str = fmt.Sprint(value)
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(str); err != nil {
+		return "", fmt.Errorf("jsonQuote: %w", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// jsonEscape escapes a value like jsonQuote without the surrounding quotes, to embed it in a quoted string
+func jsonEscape(value interface{}) (string, error) {
+	quoted, err := jsonQuote(value)
+	if err != nil {
+		return "", err
+	}
+	return quoted[1 : len(quoted)-1], nil
+}
+
+// templateReader implements the cluster read functions of a template, scoped to a namespace
+type templateReader struct {
+	processor *TemplateProcessor
+	ctx       context.Context
+	namespace string
+}
+
+// available fails if cluster reads are not configured
+func (r *templateReader) available(function string) error {
+	if r.processor.Reader == nil || r.namespace == "" {
+		return fmt.Errorf("%s is not available in this template", function)
+	}
+	return nil
+}
+
+// valuesAllowed fails if ConfigMap and Secret values may not be read
+func (r *templateReader) valuesAllowed(function string) error {
+	if err := r.available(function); err != nil {
+		return err
+	}
+	if !r.processor.AllowValueLookups {
+		return fmt.Errorf("%s is disabled, start the operator with --template-value-lookups to enable it", function)
+	}
+	return nil
+}
+
+// lookup reads a resource like the lookup function of Helm: it returns the resource, an empty map
+// if it does not exist, or a list of all resources of the kind if name is empty. Only the namespace
+// of the HTTPQueryResource and the kinds in LookupGVKs can be read.
+func (r *templateReader) lookup(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+	if err := r.available("lookup"); err != nil {
+		return nil, err
+	}
+	if namespace == "" {
+		namespace = r.namespace
+	}
+	if namespace != r.namespace {
+		return nil, fmt.Errorf("lookup: only namespace '%s' can be read, not '%s'", r.namespace, namespace)
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return nil, fmt.Errorf("lookup: %w", err)
+	}
+	gvk := gv.WithKind(kind)
+	if !slices.Contains(r.processor.LookupGVKs, gvk) {
+		return nil, fmt.Errorf("lookup: %s is not a registered kind", gvk.String())
+	}
+	if gvk.Group == "" && kind == "Secret" && !r.processor.AllowValueLookups {
+		return nil, fmt.Errorf("lookup: reading Secrets is disabled, start the operator with --template-value-lookups to enable it")
+	}
+
+	if name == "" {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gv.WithKind(kind + "List"))
+		if err := r.processor.Reader.List(r.ctx, list, client.InNamespace(namespace)); err != nil {
+			return nil, fmt.Errorf("lookup: failed to list %s: %w", kind, err)
+		}
+		return list.UnstructuredContent(), nil
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := r.processor.Reader.Get(r.ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return map[string]interface{}{}, nil
+		}
+		return nil, fmt.Errorf("lookup: failed to get %s '%s': %w", kind, name, err)
+	}
+	return obj.Object, nil
+}
+
+// configMapValue returns a key of a ConfigMap in the namespace of the HTTPQueryResource
+func (r *templateReader) configMapValue(name, key string) (string, error) {
+	if err := r.valuesAllowed("configMapValue"); err != nil {
+		return "", err
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := r.processor.Reader.Get(r.ctx, client.ObjectKey{Namespace: r.namespace, Name: name}, configMap); err != nil {
+		return "", fmt.Errorf("configMapValue: failed to get configmap '%s': %w", name, err)
+	}
+	if value, ok := configMap.Data[key]; ok {
+		return value, nil
+	}
+	if value, ok := configMap.BinaryData[key]; ok {
+		return string(value), nil
+	}
+	return "", fmt.Errorf("configMapValue: key '%s' not found in configmap '%s' in namespace '%s'", key, name, r.namespace)
+}
+
+// secretValue returns a key of a Secret in the namespace of the HTTPQueryResource
+func (r *templateReader) secretValue(name, key string) (string, error) {
+	if err := r.valuesAllowed("secretValue"); err != nil {
+		return "", err
+	}
+	secret := &corev1.Secret{}
+	if err := r.processor.Reader.Get(r.ctx, client.ObjectKey{Namespace: r.namespace, Name: name}, secret); err != nil {
+		return "", fmt.Errorf("secretValue: failed to get secret '%s': %w", name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secretValue: key '%s' not found in secret '%s' in namespace '%s'", key, name, r.namespace)
+	}
+	return string(value), nil
 }
@@ -1,10 +1,18 @@
 package util
 
 import (
+	"context"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestTemplateProcessor_ProcessTemplate(t *testing.T) {
@@ -251,3 +259,350 @@ data:
        _, err := tp.ProcessHTTPResponseToResources(template, items)
        require.Error(t, err)
 }
+
+func TestTemplateProcessor_ProcessItemsToResources_PartialFailure(t *testing.T) {
+	tp := NewTemplateProcessor()
+
+	template := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: user-{{ if not .Item.name }}{{ fail "name is required" }}{{ end }}{{ .Item.name }}
+data:
+  id: "{{ .Item.id }}"`
+
+	items := []ItemResult{{"id": "a", "name": "alice"}, {"id": "b"}, {"id": "c", "name": "carol"}}
+
+	resources, err := tp.ProcessItemsToResources(template, items, TemplateInput{})
+	require.Len(t, resources, 2)
+	assert.Equal(t, "user-alice", resources[0].GetName())
+	assert.Equal(t, "user-carol", resources[1].GetName())
+
+	var templateErr *TemplateError
+	require.ErrorAs(t, err, &templateErr)
+	assert.True(t, templateErr.Partial())
+	assert.Equal(t, []int{1}, templateErr.Indexes())
+	assert.Contains(t, err.Error(), "1 of 3 items failed to process: item 1: template error:")
+
+	t.Run("all items fail", func(t *testing.T) {
+		resources, err := tp.ProcessItemsToResources(template, []ItemResult{{"id": "b"}}, TemplateInput{})
+		assert.Empty(t, resources)
+		require.ErrorAs(t, err, &templateErr)
+		assert.False(t, templateErr.Partial())
+	})
+
+	t.Run("no items", func(t *testing.T) {
+		resources, err := tp.ProcessItemsToResources(template, nil, TemplateInput{})
+		assert.NoError(t, err)
+		assert.Empty(t, resources)
+	})
+}
+
+func TestTemplateProcessor_ProcessItemsToResources_ItemKey(t *testing.T) {
+	tp := NewTemplateProcessor()
+
+	template := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: user-{{ .ItemKey }}
+data:
+  name: "{{ .Item.name }}"`
+
+	items := []ItemResult{
+		{"id": "a", "name": "alice"},
+		{"name": "bob"},
+		{"id": "a", "name": "carol"},
+		{"id": "user@example.com", "name": "dave"},
+	}
+
+	resources, err := tp.ProcessItemsToResources(template, items, TemplateInput{ItemKey: "id"})
+	require.Len(t, resources, 2)
+	assert.Equal(t, "user-a", resources[0].GetName())
+	assert.Equal(t, "a", resources[0].GetLabels()[ItemKeyLabel])
+	assert.Equal(t, "a", resources[0].GetAnnotations()[ItemKeyAnnotation])
+	assert.Equal(t, ItemKeyLabelValue("user@example.com"), resources[1].GetLabels()[ItemKeyLabel])
+	assert.Equal(t, "user@example.com", resources[1].GetAnnotations()[ItemKeyAnnotation])
+
+	var templateErr *TemplateError
+	require.ErrorAs(t, err, &templateErr)
+	assert.Equal(t, []int{1, 2}, templateErr.Indexes())
+	assert.Contains(t, err.Error(), "item 1: key error: item key 'id' not found")
+	assert.Contains(t, err.Error(), "item 2 (key a): key error: duplicate item key, also used by item 0")
+	_, ok := templateErr.Keys()
+	assert.False(t, ok, "item 1 has no key")
+
+	t.Run("template error", func(t *testing.T) {
+		_, err := tp.ProcessItemsToResources(`{{ fail "broken" }}`, []ItemResult{{"id": 7}}, TemplateInput{ItemKey: "id"})
+		require.ErrorAs(t, err, &templateErr)
+		assert.Contains(t, err.Error(), "item 0 (key 7): template error:")
+		keys, ok := templateErr.Keys()
+		assert.True(t, ok)
+		assert.Equal(t, []string{"7"}, keys)
+	})
+}
+
+func TestTemplateProcessor_ProcessAggregateToResources(t *testing.T) {
+	tp := NewTemplateProcessor()
+
+	template := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: users
+data:
+  total: "{{ .Response.total }}"
+  page: "{{ .Headers.Get "X-Page" }}"
+  users: "{{ range $i, $item := .Items }}{{ if $i }},{{ end }}{{ $item.name }}{{ end }}"
+  regions: "{{ len .Sources.regions }}"`
+
+	items := []ItemResult{{"name": "alice"}, {"name": "bob"}}
+	input := TemplateInput{
+		Sources:  map[string][]ItemResult{"regions": {{"name": "eu"}}},
+		Response: []byte(`{"total": 2, "users": [{"name": "alice"}, {"name": "bob"}]}`),
+		Header:   http.Header{"X-Page": []string{"1"}},
+	}
+
+	resources, err := tp.ProcessAggregateToResources(template, items, input)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	data, _, _ := unstructured.NestedStringMap(resources[0].Object, "data")
+	assert.Equal(t, map[string]string{"total": "2", "page": "1", "users": "alice,bob", "regions": "1"}, data)
+	assert.NotContains(t, resources[0].GetAnnotations(), "konnektr.io/original-item")
+
+	t.Run("no response", func(t *testing.T) {
+		resources, err := tp.ProcessAggregateToResources(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: users
+data:
+  count: "{{ len .Items }}"
+  page: "{{ .Headers.Get "X-Page" }}"`, nil, TemplateInput{})
+		require.NoError(t, err)
+		data, _, _ := unstructured.NestedStringMap(resources[0].Object, "data")
+		assert.Equal(t, map[string]string{"count": "0", "page": ""}, data)
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		_, err := tp.ProcessAggregateToResources(`{{ fail "boom" }}`, items, TemplateInput{})
+		assert.ErrorContains(t, err, "aggregate template error")
+	})
+}
+
+func TestTemplateProcessor_ProcessItemsToResources_Sources(t *testing.T) {
+	tp := NewTemplateProcessor()
+
+	template := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: tenant-{{ .Item.id }}
+data:
+  regions: "{{ len .Sources.regions }}"
+  cpu: "{{ range .Joined.quotas }}{{ .cpu }}{{ end }}"`
+
+	items := []ItemResult{{"id": 1}, {"id": 2}}
+	input := TemplateInput{
+		Sources: map[string][]ItemResult{
+			"regions": {{"name": "eu"}, {"name": "us"}},
+			"quotas":  {{"tenant": 1, "cpu": "4"}},
+		},
+		Joined: []map[string][]ItemResult{
+			{"quotas": {{"tenant": 1, "cpu": "4"}}},
+			{"quotas": {}},
+		},
+	}
+
+	resources, err := tp.ProcessItemsToResources(template, items, input)
+	require.NoError(t, err)
+	require.Len(t, resources, 2)
+
+	data, _, _ := unstructured.NestedStringMap(resources[0].Object, "data")
+	assert.Equal(t, map[string]string{"regions": "2", "cpu": "4"}, data)
+	data, _, _ = unstructured.NestedStringMap(resources[1].Object, "data")
+	assert.Equal(t, map[string]string{"regions": "2", "cpu": ""}, data)
+}
+
+func TestTemplateProcessor_ToYaml(t *testing.T) {
+	tp := NewTemplateProcessor()
+
+	result, err := tp.ProcessTemplate("labels:{{ toYaml .labels | nindent 2 }}", map[string]interface{}{
+		"labels": map[string]interface{}{"team": "platform", "tier": "backend"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "labels:\n  team: platform\n  tier: backend", result)
+
+	result, err = tp.ProcessTemplate("{{ toYaml .items }}", map[string]interface{}{"items": []string{"a", "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, "- a\n- b", result)
+}
+
+func TestTemplateProcessor_FromYaml(t *testing.T) {
+	tp := NewTemplateProcessor()
+
+	result, err := tp.ProcessTemplate(`{{ $config := fromYaml .config }}{{ $config.region }}/{{ index $config.zones 1 }}`, map[string]interface{}{
+		"config": "region: eu-west-1\nzones: [a, b]",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1/b", result)
+
+	result, err = tp.ProcessTemplate(`{{ (fromYaml .config).id }}`, map[string]interface{}{"config": `{"id": 7}`})
+	require.NoError(t, err)
+	assert.Equal(t, "7", result)
+
+	_, err = tp.ProcessTemplate(`{{ fromYaml .config }}`, map[string]interface{}{"config": "- not\n- an object"})
+	assert.ErrorContains(t, err, "fromYaml")
+}
+
+func TestTemplateProcessor_JSONQuote(t *testing.T) {
+	tp := NewTemplateProcessor()
+	data := map[string]interface{}{"name": "say \"hi\"\n<b>\\o/</b>", "count": 3, "missing": nil}
+
+	result, err := tp.ProcessTemplate(`{{ jsonQuote .name }} {{ jsonQuote .count }} {{ jsonQuote .missing }}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, `"say \"hi\"\n<b>\\o/</b>" "3" ""`, result)
+
+	// The quoted value is a valid YAML scalar that round-trips
+	rendered, err := tp.ProcessTemplate("apiVersion: v1\nkind: ConfigMap\ndata:\n  name: {{ jsonQuote .name }}", data)
+	require.NoError(t, err)
+	resources, err := tp.ParseResources(rendered)
+	require.NoError(t, err)
+	value, _, _ := unstructured.NestedString(resources[0].Object, "data", "name")
+	assert.Equal(t, data["name"], value)
+}
+
+func TestTemplateProcessor_JSONEscape(t *testing.T) {
+	tp := NewTemplateProcessor()
+
+	result, err := tp.ProcessTemplate(`"user-{{ jsonEscape .name }}"`, map[string]interface{}{"name": "a\"b\\c\td"})
+	require.NoError(t, err)
+	assert.Equal(t, `"user-a\"b\\c\td"`, result)
+}
+
+// newLookupProcessor returns a processor reading a fake cluster
+func newLookupProcessor(t *testing.T, allowValueLookups bool) *TemplateProcessor {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "team-a"},
+			Data:       map[string]string{"region": "eu-west-1"},
+			BinaryData: map[string][]byte{"logo": []byte("png")},
+		},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "quotas", Namespace: "team-a"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "team-b"}},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "team-a"},
+			Data:       map[string][]byte{"token": []byte("s3cr3t")},
+		},
+	).Build()
+	return &TemplateProcessor{
+		Reader: fakeClient,
+		LookupGVKs: []schema.GroupVersionKind{
+			corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+			corev1.SchemeGroupVersion.WithKind("Secret"),
+		},
+		AllowValueLookups: allowValueLookups,
+	}
+}
+
+// renderInNamespace renders a template like a resource template of an HTTPQueryResource in team-a
+func renderInNamespace(tp *TemplateProcessor, templateStr string) (string, error) {
+	return tp.processTemplate(templateStr, nil, TemplateInput{Namespace: "team-a", Context: context.Background()})
+}
+
+func TestTemplateProcessor_Lookup(t *testing.T) {
+	tp := newLookupProcessor(t, false)
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+		wantErr  string
+	}{
+		{
+			name:     "get",
+			template: `{{ (lookup "v1" "ConfigMap" "team-a" "settings").data.region }}`,
+			expected: "eu-west-1",
+		},
+		{
+			name:     "defaults to the namespace of the resource",
+			template: `{{ (lookup "v1" "ConfigMap" "" "settings").data.region }}`,
+			expected: "eu-west-1",
+		},
+		{
+			name:     "not found",
+			template: `{{ if not (lookup "v1" "ConfigMap" "" "missing") }}absent{{ end }}`,
+			expected: "absent",
+		},
+		{
+			name:     "list",
+			template: `{{ range (lookup "v1" "ConfigMap" "" "").items }}{{ .metadata.name }} {{ end }}`,
+			expected: "quotas settings ",
+		},
+		{
+			name:     "other namespace",
+			template: `{{ lookup "v1" "ConfigMap" "team-b" "settings" }}`,
+			wantErr:  "only namespace 'team-a' can be read",
+		},
+		{
+			name:     "unregistered kind",
+			template: `{{ lookup "apps/v1" "Deployment" "" "api" }}`,
+			wantErr:  "apps/v1, Kind=Deployment is not a registered kind",
+		},
+		{
+			name:     "secrets are disabled",
+			template: `{{ lookup "v1" "Secret" "" "api" }}`,
+			wantErr:  "reading Secrets is disabled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := renderInNamespace(tp, tt.template)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("secrets with value lookups", func(t *testing.T) {
+		result, err := renderInNamespace(newLookupProcessor(t, true), `{{ (lookup "v1" "Secret" "" "api").data.token | b64dec }}`)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", result)
+	})
+
+	t.Run("without cluster access", func(t *testing.T) {
+		_, err := tp.ProcessTemplate(`{{ lookup "v1" "ConfigMap" "" "settings" }}`, nil)
+		assert.ErrorContains(t, err, "lookup is not available in this template")
+	})
+}
+
+func TestTemplateProcessor_ConfigMapValue(t *testing.T) {
+	tp := newLookupProcessor(t, true)
+
+	result, err := renderInNamespace(tp, `{{ configMapValue "settings" "region" }}/{{ configMapValue "settings" "logo" }}`)
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1/png", result)
+
+	_, err = renderInNamespace(tp, `{{ configMapValue "settings" "zone" }}`)
+	assert.ErrorContains(t, err, "key 'zone' not found in configmap 'settings' in namespace 'team-a'")
+	_, err = renderInNamespace(tp, `{{ configMapValue "missing" "region" }}`)
+	assert.ErrorContains(t, err, "failed to get configmap 'missing'")
+
+	_, err = renderInNamespace(newLookupProcessor(t, false), `{{ configMapValue "settings" "region" }}`)
+	assert.ErrorContains(t, err, "configMapValue is disabled")
+}
+
+func TestTemplateProcessor_SecretValue(t *testing.T) {
+	tp := newLookupProcessor(t, true)
+
+	result, err := renderInNamespace(tp, `{{ secretValue "api" "token" }}`)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", result)
+
+	_, err = renderInNamespace(tp, `{{ secretValue "api" "password" }}`)
+	assert.ErrorContains(t, err, "key 'password' not found in secret 'api' in namespace 'team-a'")
+
+	_, err = renderInNamespace(newLookupProcessor(t, false), `{{ secretValue "api" "token" }}`)
+	assert.ErrorContains(t, err, "secretValue is disabled")
+}
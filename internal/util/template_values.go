@@ -0,0 +1,51 @@
+package util
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+// ResolveTemplateValues resolves the values exposed to request templates, reading Secret and
+// ConfigMap keys on every call so that changes are picked up on the next poll.
+func (ar *AuthResolver) ResolveTemplateValues(ctx context.Context, namespace string, values []httpv1alpha1.HTTPTemplateValue) (map[string]string, error) {
+	resolved := make(map[string]string, len(values))
+	for _, value := range values {
+		switch {
+		case value.SecretKeyRef != nil:
+			ref := value.SecretKeyRef
+			refNamespace := defaultString(ref.Namespace, namespace)
+			secret := &corev1.Secret{}
+			if err := ar.get(ctx, "secret", ref.Name, refNamespace, secret); err != nil {
+				return nil, fmt.Errorf("value '%s': %w", value.Name, err)
+			}
+			data, exists := secret.Data[ref.Key]
+			if !exists {
+				return nil, fmt.Errorf("value '%s': key '%s' not found in secret '%s' in namespace '%s'", value.Name, ref.Key, ref.Name, refNamespace)
+			}
+			resolved[value.Name] = string(data)
+		case value.ConfigMapKeyRef != nil:
+			ref := value.ConfigMapKeyRef
+			refNamespace := defaultString(ref.Namespace, namespace)
+			configMap := &corev1.ConfigMap{}
+			if err := ar.get(ctx, "configmap", ref.Name, refNamespace, configMap); err != nil {
+				return nil, fmt.Errorf("value '%s': %w", value.Name, err)
+			}
+			data, exists := configMap.Data[ref.Key]
+			if !exists {
+				binaryData, binaryExists := configMap.BinaryData[ref.Key]
+				if !binaryExists {
+					return nil, fmt.Errorf("value '%s': key '%s' not found in configmap '%s' in namespace '%s'", value.Name, ref.Key, ref.Name, refNamespace)
+				}
+				data = string(binaryData)
+			}
+			resolved[value.Name] = data
+		default:
+			resolved[value.Name] = value.Value
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+func TestAuthResolver_ResolveTemplateValues(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Data:       map[string][]byte{"tenant": []byte("acme")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "shared"},
+			Data:       map[string]string{"region": "eu-west-1"},
+		},
+	).Build()
+	resolver := NewAuthResolver(fakeClient, logr.Discard())
+
+	t.Run("literal, secret and configmap values", func(t *testing.T) {
+		values, err := resolver.ResolveTemplateValues(context.Background(), "default", []httpv1alpha1.HTTPTemplateValue{
+			{Name: "pageSize", Value: "50"},
+			{Name: "tenant", SecretKeyRef: &httpv1alpha1.HTTPKeyRef{Name: "api", Key: "tenant"}},
+			{Name: "region", ConfigMapKeyRef: &httpv1alpha1.HTTPKeyRef{Name: "settings", Namespace: "shared", Key: "region"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"pageSize": "50", "tenant": "acme", "region": "eu-west-1"}, values)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := resolver.ResolveTemplateValues(context.Background(), "default", []httpv1alpha1.HTTPTemplateValue{
+			{Name: "tenant", SecretKeyRef: &httpv1alpha1.HTTPKeyRef{Name: "api", Key: "missing"}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "key 'missing' not found in secret 'api'")
+	})
+
+	t.Run("missing configmap", func(t *testing.T) {
+		_, err := resolver.ResolveTemplateValues(context.Background(), "default", []httpv1alpha1.HTTPTemplateValue{
+			{Name: "region", ConfigMapKeyRef: &httpv1alpha1.HTTPKeyRef{Name: "settings", Key: "region"}},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "configmap 'settings' not found in namespace 'default'")
+	})
+}
@@ -0,0 +1,201 @@
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+const (
+	// maxCachedTLSClients bounds the number of HTTP clients kept for custom TLS configurations.
+	maxCachedTLSClients = 64
+)
+
+// ResolvedTLSConfig holds the resolved TLS configuration
+type ResolvedTLSConfig struct {
+	// CACert holds PEM-encoded CA certificates trusted in addition to the system roots
+	CACert []byte
+	// ClientCert and ClientKey hold the PEM-encoded client certificate and key for mutual TLS
+	ClientCert []byte
+	ClientKey  []byte
+	ServerName string
+	// InsecureSkipVerify disables server certificate verification
+	InsecureSkipVerify bool
+}
+
+// ResolveTLSConfig resolves CA bundles and client certificates from Kubernetes Secrets and ConfigMaps.
+// It is called for every request so that rotated certificates are picked up without a restart.
+func (ar *AuthResolver) ResolveTLSConfig(ctx context.Context, namespace string, tlsSpec *httpv1alpha1.HTTPTLSSpec) (*ResolvedTLSConfig, error) {
+	resolved := &ResolvedTLSConfig{
+		ServerName:         tlsSpec.ServerName,
+		InsecureSkipVerify: tlsSpec.InsecureSkipVerify,
+	}
+
+	if tlsSpec.InsecureSkipVerify {
+		ar.Log.Info("WARNING: TLS certificate verification is disabled (insecureSkipVerify: true); requests are vulnerable to man-in-the-middle attacks",
+			"namespace", namespace)
+	}
+
+	if caRef := tlsSpec.CARef; caRef != nil {
+		caNamespace := caRef.Namespace
+		if caNamespace == "" {
+			caNamespace = namespace
+		}
+		key := caRef.Key
+		if key == "" {
+			key = "ca.crt"
+		}
+		kind := caRef.Kind
+		if kind == "" {
+			kind = "ConfigMap"
+		}
+
+		var data []byte
+		switch kind {
+		case "Secret":
+			secret := &corev1.Secret{}
+			if err := ar.get(ctx, "CA secret", caRef.Name, caNamespace, secret); err != nil {
+				return nil, err
+			}
+			data = secret.Data[key]
+		case "ConfigMap":
+			configMap := &corev1.ConfigMap{}
+			if err := ar.get(ctx, "CA configmap", caRef.Name, caNamespace, configMap); err != nil {
+				return nil, err
+			}
+			if value, exists := configMap.Data[key]; exists {
+				data = []byte(value)
+			} else {
+				data = configMap.BinaryData[key]
+			}
+		default:
+			return nil, fmt.Errorf("unsupported CA reference kind: %s", kind)
+		}
+
+		if len(data) == 0 {
+			return nil, fmt.Errorf("CA bundle key '%s' not found in %s '%s' in namespace '%s'", key, kind, caRef.Name, caNamespace)
+		}
+		resolved.CACert = data
+	}
+
+	if certRef := tlsSpec.ClientCertRef; certRef != nil {
+		certNamespace := certRef.Namespace
+		if certNamespace == "" {
+			certNamespace = namespace
+		}
+
+		secret := &corev1.Secret{}
+		if err := ar.get(ctx, "client certificate secret", certRef.Name, certNamespace, secret); err != nil {
+			return nil, err
+		}
+		resolved.ClientCert = secret.Data[corev1.TLSCertKey]
+		resolved.ClientKey = secret.Data[corev1.TLSPrivateKeyKey]
+		if len(resolved.ClientCert) == 0 || len(resolved.ClientKey) == 0 {
+			return nil, fmt.Errorf("client certificate secret '%s' in namespace '%s' must contain '%s' and '%s'",
+				certRef.Name, certNamespace, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+		}
+	}
+
+	return resolved, nil
+}
+
+// get fetches a named object, returning descriptive errors.
+func (ar *AuthResolver) get(ctx context.Context, description, name, namespace string, obj client.Object) error {
+	if err := ar.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%s '%s' not found in namespace '%s'", description, name, namespace)
+		}
+		return fmt.Errorf("failed to get %s '%s' in namespace '%s': %w", description, name, namespace, err)
+	}
+	return nil
+}
+
+// fingerprint returns a hash identifying the TLS configuration.
+func (c *ResolvedTLSConfig) fingerprint() string {
+	return credentialsFingerprint([]string{
+		string(c.CACert),
+		string(c.ClientCert),
+		string(c.ClientKey),
+		c.ServerName,
+		fmt.Sprint(c.InsecureSkipVerify),
+	})
+}
+
+// TLSConfig builds a crypto/tls configuration.
+func (c *ResolvedTLSConfig) TLSConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+		// Only ever true when explicitly requested in the spec
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if len(c.CACert) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(c.CACert) {
+			return nil, fmt.Errorf("CA bundle does not contain any valid PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(c.ClientCert) > 0 || len(c.ClientKey) > 0 {
+		cert, err := tls.X509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// httpClientFor returns the HTTP client to use for the given TLS configuration.
+// Clients are cached by the content of the configuration, so rotated certificates get a new client.
+func (r *RESTClient) httpClientFor(tlsConfig *ResolvedTLSConfig) (*http.Client, error) {
+	if tlsConfig == nil {
+		return r.client, nil
+	}
+
+	key := tlsConfig.fingerprint()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cached, exists := r.tlsClients[key]; exists {
+		return cached, nil
+	}
+
+	config, err := tlsConfig.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config
+
+	// Drop stale clients (e.g. for rotated certificates) once the cache grows too large
+	if len(r.tlsClients) >= maxCachedTLSClients {
+		for cachedKey, cached := range r.tlsClients {
+			cached.CloseIdleConnections()
+			delete(r.tlsClients, cachedKey)
+		}
+	}
+
+	httpClient := &http.Client{
+		Transport: transport,
+	}
+	r.tlsClients[key] = httpClient
+	return httpClient, nil
+}
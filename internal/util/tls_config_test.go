@@ -0,0 +1,224 @@
+package util
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+// generateCertificate creates a self-signed certificate usable as both CA and leaf for tests.
+func generateCertificate(t *testing.T, commonName string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: commonName},
+		DNSNames:              []string{commonName},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+func TestAuthResolver_ResolveTLSConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	caPEM, _ := generateCertificate(t, "ca.example.com")
+	certPEM, keyPEM := generateCertificate(t, "client")
+
+	objects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca-bundle", Namespace: "default"},
+			Data:       map[string]string{"ca.crt": string(caPEM)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca-secret", Namespace: "certs"},
+			Data:       map[string][]byte{"bundle.pem": caPEM},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "client-tls", Namespace: "default"},
+			Type:       corev1.SecretTypeTLS,
+			Data:       map[string][]byte{"tls.crt": certPEM, "tls.key": keyPEM},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "incomplete-tls", Namespace: "default"},
+			Data:       map[string][]byte{"tls.crt": certPEM},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		tlsSpec  *httpv1alpha1.HTTPTLSSpec
+		expected *ResolvedTLSConfig
+		wantErr  string
+	}{
+		{
+			name:     "CA from configmap with defaults",
+			tlsSpec:  &httpv1alpha1.HTTPTLSSpec{CARef: &httpv1alpha1.HTTPTLSCARef{Name: "ca-bundle"}},
+			expected: &ResolvedTLSConfig{CACert: caPEM},
+		},
+		{
+			name: "CA from secret in another namespace with custom key",
+			tlsSpec: &httpv1alpha1.HTTPTLSSpec{CARef: &httpv1alpha1.HTTPTLSCARef{
+				Kind: "Secret", Name: "ca-secret", Namespace: "certs", Key: "bundle.pem",
+			}},
+			expected: &ResolvedTLSConfig{CACert: caPEM},
+		},
+		{
+			name: "client certificate and server name",
+			tlsSpec: &httpv1alpha1.HTTPTLSSpec{
+				ClientCertRef: &httpv1alpha1.HTTPTLSClientCertRef{Name: "client-tls"},
+				ServerName:    "api.internal",
+			},
+			expected: &ResolvedTLSConfig{ClientCert: certPEM, ClientKey: keyPEM, ServerName: "api.internal"},
+		},
+		{
+			name:     "insecure skip verify",
+			tlsSpec:  &httpv1alpha1.HTTPTLSSpec{InsecureSkipVerify: true},
+			expected: &ResolvedTLSConfig{InsecureSkipVerify: true},
+		},
+		{
+			name:    "missing CA configmap",
+			tlsSpec: &httpv1alpha1.HTTPTLSSpec{CARef: &httpv1alpha1.HTTPTLSCARef{Name: "missing"}},
+			wantErr: "not found",
+		},
+		{
+			name:    "missing CA key",
+			tlsSpec: &httpv1alpha1.HTTPTLSSpec{CARef: &httpv1alpha1.HTTPTLSCARef{Name: "ca-bundle", Key: "other.crt"}},
+			wantErr: "CA bundle key 'other.crt' not found",
+		},
+		{
+			name:    "incomplete client certificate secret",
+			tlsSpec: &httpv1alpha1.HTTPTLSSpec{ClientCertRef: &httpv1alpha1.HTTPTLSClientCertRef{Name: "incomplete-tls"}},
+			wantErr: "must contain 'tls.crt' and 'tls.key'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+			resolver := NewAuthResolver(fakeClient, logr.Discard())
+
+			result, err := resolver.ResolveTLSConfig(context.Background(), "default", tt.tlsSpec)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestRESTClient_Execute_TLS(t *testing.T) {
+	serverCertPEM, serverKeyPEM := generateCertificate(t, "api.internal")
+	clientCertPEM, clientKeyPEM := generateCertificate(t, "client")
+
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	require.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM(clientCertPEM))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Len(t, r.TLS.PeerCertificates, 1)
+		w.Write([]byte(`[{"client": "` + r.TLS.PeerCertificates[0].Subject.CommonName + `"}]`))
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	client := NewRESTClient()
+
+	t.Run("private CA and client certificate", func(t *testing.T) {
+		items, err := client.Execute(context.Background(), HTTPConfig{
+			URL: server.URL,
+			TLS: &ResolvedTLSConfig{
+				CACert:     serverCertPEM,
+				ClientCert: clientCertPEM,
+				ClientKey:  clientKeyPEM,
+				ServerName: "api.internal",
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		assert.Equal(t, "client", items[0]["client"])
+	})
+
+	t.Run("unknown CA is rejected", func(t *testing.T) {
+		_, err := client.Execute(context.Background(), HTTPConfig{
+			URL: server.URL,
+			TLS: &ResolvedTLSConfig{ClientCert: clientCertPEM, ClientKey: clientKeyPEM, ServerName: "api.internal"},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("missing client certificate is rejected", func(t *testing.T) {
+		_, err := client.Execute(context.Background(), HTTPConfig{
+			URL: server.URL,
+			TLS: &ResolvedTLSConfig{CACert: serverCertPEM, ServerName: "api.internal"},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid CA bundle", func(t *testing.T) {
+		_, err := client.Execute(context.Background(), HTTPConfig{
+			URL: server.URL,
+			TLS: &ResolvedTLSConfig{CACert: []byte("not a certificate")},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid TLS configuration")
+	})
+
+	t.Run("clients are reused for identical configuration", func(t *testing.T) {
+		config := &ResolvedTLSConfig{CACert: serverCertPEM, ServerName: "api.internal"}
+		first, err := client.httpClientFor(config)
+		require.NoError(t, err)
+		second, err := client.httpClientFor(&ResolvedTLSConfig{CACert: serverCertPEM, ServerName: "api.internal"})
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+
+		rotated, err := client.httpClientFor(&ResolvedTLSConfig{CACert: clientCertPEM, ServerName: "api.internal"})
+		require.NoError(t, err)
+		assert.NotSame(t, first, rotated)
+	})
+}
@@ -0,0 +1,136 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const (
+	// TokenExpiryDelta is how long before expiry a cached token is refreshed.
+	TokenExpiryDelta = 30 * time.Second
+	// TokenCacheIdleTimeout is how long an unused token source is kept, so that sources of
+	// deleted or reconfigured resources do not stay in memory for the life of the process.
+	TokenCacheIdleTimeout = time.Hour
+)
+
+// TokenCache caches OAuth2 token sources so that tokens are reused across requests until they expire.
+// It is safe for concurrent use.
+type TokenCache struct {
+	mu      sync.Mutex
+	entries map[TokenCacheKey]*tokenCacheEntry
+	// now is overridden in tests
+	now func() time.Time
+}
+
+// TokenCacheKey identifies a cached token source.
+type TokenCacheKey struct {
+	TokenURL  string
+	ClientID  string
+	Scopes    string
+	GrantType string
+	// SecretRef is the namespace/name of the Secret holding the credentials
+	SecretRef string
+	// TLS is the fingerprint of the TLS configuration of the token request
+	TLS string
+}
+
+type tokenCacheEntry struct {
+	// fingerprint of the credentials the source was created from
+	fingerprint string
+	source      oauth2.TokenSource
+	lastUsed    time.Time
+}
+
+// tokenSourceFunc adapts a function to the oauth2.TokenSource interface.
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) { return f() }
+
+// NewTokenCache creates a new TokenCache
+func NewTokenCache() *TokenCache {
+	return &TokenCache{
+		entries: make(map[TokenCacheKey]*tokenCacheEntry),
+		now:     time.Now,
+	}
+}
+
+// NewTokenCacheKey builds a cache key, normalising the order of the scopes.
+func NewTokenCacheKey(tokenURL, clientID, scopes string) TokenCacheKey {
+	scopeList := strings.Fields(scopes)
+	slices.Sort(scopeList)
+	return TokenCacheKey{
+		TokenURL: tokenURL,
+		ClientID: clientID,
+		Scopes:   strings.Join(scopeList, " "),
+	}
+}
+
+// Token returns a valid token for key, fetching a new one through fetch only when the cached
+// token is missing or about to expire. Cached tokens are discarded when the credentials
+// fingerprint changes, e.g. because the referenced Secret was rotated, and sources that
+// have not been used for TokenCacheIdleTimeout are evicted.
+func (c *TokenCache) Token(key TokenCacheKey, credentials []string, fetch func() (*oauth2.Token, error)) (*oauth2.Token, error) {
+	fingerprint := credentialsFingerprint(credentials)
+
+	c.mu.Lock()
+	now := c.now()
+	c.evictIdle(now)
+	entry, exists := c.entries[key]
+	if !exists || entry.fingerprint != fingerprint {
+		entry = &tokenCacheEntry{
+			fingerprint: fingerprint,
+			source:      oauth2.ReuseTokenSourceWithExpiry(nil, tokenSourceFunc(fetch), TokenExpiryDelta),
+		}
+		c.entries[key] = entry
+	}
+	entry.lastUsed = now
+	c.mu.Unlock()
+
+	// ReuseTokenSource serialises refreshes, so concurrent callers share a single fetch
+	return entry.source.Token()
+}
+
+// InvalidateSecret removes the token sources created from the credentials of the Secret
+// with the given namespace/name, so that the next request fetches a new token.
+func (c *TokenCache) InvalidateSecret(secretRef string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.SecretRef == secretRef {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// evictIdle removes token sources that have not been used since TokenCacheIdleTimeout.
+// The caller must hold c.mu.
+func (c *TokenCache) evictIdle(now time.Time) {
+	for key, entry := range c.entries {
+		if now.Sub(entry.lastUsed) > TokenCacheIdleTimeout {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Len returns the number of cached token sources.
+func (c *TokenCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// credentialsFingerprint returns a hash of the credentials so that secrets are not kept in the cache key.
+func credentialsFingerprint(credentials []string) string {
+	hash := sha256.New()
+	for _, credential := range credentials {
+		hash.Write([]byte(credential))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
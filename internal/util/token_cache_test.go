@@ -0,0 +1,184 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestTokenCache_Token(t *testing.T) {
+	t.Run("reuses valid tokens", func(t *testing.T) {
+		cache := NewTokenCache()
+		key := NewTokenCacheKey("https://auth.example.com/token", "client", "read write")
+		var fetches int
+		fetch := func() (*oauth2.Token, error) {
+			fetches++
+			return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", fetches), Expiry: time.Now().Add(time.Hour)}, nil
+		}
+
+		for range 3 {
+			token, err := cache.Token(key, []string{"secret"}, fetch)
+			require.NoError(t, err)
+			assert.Equal(t, "token-1", token.AccessToken)
+		}
+		assert.Equal(t, 1, fetches)
+	})
+
+	t.Run("refreshes tokens about to expire", func(t *testing.T) {
+		cache := NewTokenCache()
+		key := NewTokenCacheKey("https://auth.example.com/token", "client", "")
+		var fetches int
+		fetch := func() (*oauth2.Token, error) {
+			fetches++
+			return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", fetches), Expiry: time.Now().Add(TokenExpiryDelta / 2)}, nil
+		}
+
+		_, err := cache.Token(key, nil, fetch)
+		require.NoError(t, err)
+		token, err := cache.Token(key, nil, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "token-2", token.AccessToken)
+	})
+
+	t.Run("discards tokens when credentials change", func(t *testing.T) {
+		cache := NewTokenCache()
+		key := NewTokenCacheKey("https://auth.example.com/token", "client", "")
+		var fetches int
+		fetch := func() (*oauth2.Token, error) {
+			fetches++
+			return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", fetches), Expiry: time.Now().Add(time.Hour)}, nil
+		}
+
+		_, err := cache.Token(key, []string{"old-secret"}, fetch)
+		require.NoError(t, err)
+		token, err := cache.Token(key, []string{"new-secret"}, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "token-2", token.AccessToken)
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("discards tokens of an invalidated secret", func(t *testing.T) {
+		cache := NewTokenCache()
+		key := NewTokenCacheKey("https://auth.example.com/token", "client", "")
+		key.SecretRef = "team-a/api-credentials"
+		other := NewTokenCacheKey("https://auth.example.com/token", "client", "")
+		other.SecretRef = "team-b/api-credentials"
+		var fetches int
+		fetch := func() (*oauth2.Token, error) {
+			fetches++
+			return &oauth2.Token{AccessToken: fmt.Sprintf("token-%d", fetches), Expiry: time.Now().Add(time.Hour)}, nil
+		}
+
+		_, err := cache.Token(key, []string{"secret"}, fetch)
+		require.NoError(t, err)
+		_, err = cache.Token(other, []string{"secret"}, fetch)
+		require.NoError(t, err)
+
+		cache.InvalidateSecret("team-a/api-credentials")
+		assert.Equal(t, 1, cache.Len())
+		token, err := cache.Token(key, []string{"secret"}, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, "token-3", token.AccessToken)
+	})
+
+	t.Run("idle sources are evicted", func(t *testing.T) {
+		cache := NewTokenCache()
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+		key := NewTokenCacheKey("https://auth.example.com/token", "client", "")
+		otherKey := NewTokenCacheKey("https://auth.example.com/token", "other", "")
+		var fetches int
+		fetch := func() (*oauth2.Token, error) {
+			fetches++
+			return &oauth2.Token{AccessToken: "token", Expiry: now.Add(24 * time.Hour)}, nil
+		}
+
+		_, err := cache.Token(key, nil, fetch)
+		require.NoError(t, err)
+		_, err = cache.Token(otherKey, nil, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 2, cache.Len())
+
+		now = now.Add(TokenCacheIdleTimeout / 2)
+		_, err = cache.Token(key, nil, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fetches, "token should be reused while the source is in use")
+
+		// otherKey has been idle for longer than the timeout, key has not
+		now = now.Add(TokenCacheIdleTimeout/2 + time.Minute)
+		_, err = cache.Token(key, nil, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, 1, cache.Len())
+		assert.Equal(t, 2, fetches)
+	})
+
+	t.Run("scope order does not matter", func(t *testing.T) {
+		assert.Equal(t,
+			NewTokenCacheKey("https://auth.example.com/token", "client", "write read"),
+			NewTokenCacheKey("https://auth.example.com/token", "client", "read  write"))
+	})
+}
+
+func TestRESTClient_OAuth2TokenCaching(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token": "cached-token", "token_type": "Bearer", "expires_in": 3600}`))
+	}))
+	defer tokenServer.Close()
+
+	apiServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer cached-token", r.Header.Get("Authorization"))
+		w.Write([]byte(`[{"id": 1}]`))
+	}))
+	defer apiServer.Close()
+
+	client := NewRESTClient()
+	authConfig := map[string]string{
+		"clientId":     "client",
+		"clientSecret": "secret",
+		"tokenUrl":     tokenServer.URL,
+		"scopes":       "read",
+	}
+
+	// Poll and callbacks share the cached token, including concurrent requests
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Execute(context.Background(), HTTPConfig{URL: apiServer.URL, AuthType: "oauth2", AuthConfig: authConfig})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	err := client.ExecuteStatusUpdate(context.Background(), HTTPStatusUpdateConfig{
+		URL:          apiServer.URL,
+		Method:       "POST",
+		BodyTemplate: `{}`,
+		AuthType:     "oauth2",
+		AuthConfig:   authConfig,
+	}, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), tokenRequests.Load())
+
+	// A rotated client secret requests a new token
+	rotated := map[string]string{}
+	for k, v := range authConfig {
+		rotated[k] = v
+	}
+	rotated["clientSecret"] = "rotated-secret"
+	_, err = client.Execute(context.Background(), HTTPConfig{URL: apiServer.URL, AuthType: "oauth2", AuthConfig: rotated})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), tokenRequests.Load())
+}
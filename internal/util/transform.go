@@ -0,0 +1,80 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/itchyny/gojq"
+)
+
+// DefaultTransformTimeout bounds a single run of a transform
+const DefaultTransformTimeout = 5 * time.Second
+
+// CompileTransform compiles a jq program that transforms a response before its items are extracted.
+// The environment of the operator is hidden from the program.
+func CompileTransform(program string) (*gojq.Code, error) {
+	query, err := gojq.Parse(program)
+	if err != nil {
+		var parseErr *gojq.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("invalid jq program: %v at offset %d", parseErr, parseErr.Offset)
+		}
+		return nil, fmt.Errorf("invalid jq program: %w", err)
+	}
+	code, err := gojq.Compile(query, gojq.WithEnvironLoader(func() []string { return nil }))
+	if err != nil {
+		return nil, fmt.Errorf("invalid jq program: %w", err)
+	}
+	return code, nil
+}
+
+// TransformResponse runs a jq program on a JSON response and returns the JSON result.
+// A program producing several values, like `.users[]`, returns them as an array.
+func TransformResponse(ctx context.Context, body []byte, program string) ([]byte, error) {
+	code, err := CompileTransform(program)
+	if err != nil {
+		return nil, err
+	}
+
+	var input interface{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &input); err != nil {
+			return nil, fmt.Errorf("transform: response is not JSON: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultTransformTimeout)
+	defer cancel()
+	results := []interface{}{}
+	iter := code.RunWithContext(ctx, input)
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := value.(error); ok {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				break
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, fmt.Errorf("transform: timed out after %s", DefaultTransformTimeout)
+			}
+			return nil, fmt.Errorf("transform: %w", err)
+		}
+		results = append(results, value)
+	}
+
+	var result interface{} = results
+	if len(results) == 1 {
+		result = results[0]
+	}
+	transformed, err := gojq.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("transform: %w", err)
+	}
+	return transformed, nil
+}
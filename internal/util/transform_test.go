@@ -0,0 +1,70 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformResponse(t *testing.T) {
+	t.Setenv("TRANSFORM_TEST_SECRET", "hunter2")
+
+	body := `{"users": [{"id": 1, "profile": {"name": "alice"}}, {"id": 2, "profile": {"name": "bob"}}]}`
+	tests := []struct {
+		name     string
+		program  string
+		expected string
+		wantErr  string
+	}{
+		{name: "single value", program: `[.users[] | {id, name: .profile.name}]`, expected: `[{"id": 1, "name": "alice"}, {"id": 2, "name": "bob"}]`},
+		{name: "several values become an array", program: `.users[] | .profile`, expected: `[{"name": "alice"}, {"name": "bob"}]`},
+		{name: "no values", program: `empty`, expected: `[]`},
+		{name: "halt stops the program", program: `.users[0], halt, .users[1]`, expected: `{"id": 1, "profile": {"name": "alice"}}`},
+		{name: "environment is hidden", program: `{secret: $ENV.TRANSFORM_TEST_SECRET}`, expected: `{"secret": null}`},
+		{name: "parse error", program: `.users[] |`, wantErr: "invalid jq program: unexpected EOF at offset 10"},
+		{name: "undefined function", program: `.users | flatten_all`, wantErr: "invalid jq program: function not defined: flatten_all/0"},
+		{name: "runtime error", program: `.users.name`, wantErr: "transform: expected an object but got: array"},
+		{name: "error function", program: `error("no users")`, wantErr: "transform: error: no users"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transformed, err := TransformResponse(context.Background(), []byte(body), tt.program)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(transformed))
+		})
+	}
+
+	t.Run("not JSON", func(t *testing.T) {
+		_, err := TransformResponse(context.Background(), []byte(`<users/>`), `.`)
+		assert.ErrorContains(t, err, "transform: response is not JSON")
+	})
+}
+
+func TestRESTClient_Query_Transform(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": {"groups": [{"name": "admins", "members": ["alice", "bob"]}, {"name": "users", "members": ["carol"]}]}}`))
+	}))
+	defer server.Close()
+
+	// The transform runs before the response path is applied
+	result, err := NewRESTClient().Query(context.Background(), HTTPConfig{
+		URL:          server.URL,
+		Transform:    `{members: [.data.groups[] | .name as $group | .members[] | {name: ., group: $group}]}`,
+		ResponsePath: "members",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []ItemResult{
+		{"name": "alice", "group": "admins"},
+		{"name": "bob", "group": "admins"},
+		{"name": "carol", "group": "users"},
+	}, result.Items)
+}
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+	"github.com/konnektr-io/http-query-operator/internal/util"
+)
+
+// SetupHTTPQueryResourceWebhookWithManager registers the validating webhook of HTTPQueryResources in the manager.
+func SetupHTTPQueryResourceWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&httpv1alpha1.HTTPQueryResource{}).
+		WithValidator(&HTTPQueryResourceCustomValidator{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-konnektr-io-v1alpha1-httpqueryresource,mutating=false,failurePolicy=fail,sideEffects=None,groups=konnektr.io,resources=httpqueryresources,verbs=create;update,versions=v1alpha1,name=vhttpqueryresource-v1alpha1.konnektr.io,admissionReviewVersions=v1
+
+// HTTPQueryResourceCustomValidator validates the expressions of HTTPQueryResources that the CRD schema
+// cannot check: the CEL filters and jq transforms of the request and its sources.
+type HTTPQueryResourceCustomValidator struct{}
+
+var _ webhook.CustomValidator = &HTTPQueryResourceCustomValidator{}
+
+// ValidateCreate validates a new HTTPQueryResource.
+func (v *HTTPQueryResourceCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, validateHTTPQueryResource(obj)
+}
+
+// ValidateUpdate validates an updated HTTPQueryResource.
+func (v *HTTPQueryResourceCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, validateHTTPQueryResource(newObj)
+}
+
+// ValidateDelete allows every deletion.
+func (v *HTTPQueryResourceCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func validateHTTPQueryResource(obj runtime.Object) error {
+	httpQueryResource, ok := obj.(*httpv1alpha1.HTTPQueryResource)
+	if !ok {
+		return fmt.Errorf("expected an HTTPQueryResource but got %T", obj)
+	}
+
+	specPath := field.NewPath("spec")
+	allErrs := validateHTTPSpec(specPath.Child("http"), &httpQueryResource.Spec.HTTP)
+	for i := range httpQueryResource.Spec.Sources {
+		allErrs = append(allErrs, validateHTTPSpec(specPath.Child("sources").Index(i), &httpQueryResource.Spec.Sources[i].HTTPSpec)...)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(httpv1alpha1.GroupVersion.WithKind("HTTPQueryResource").GroupKind(), httpQueryResource.Name, allErrs)
+}
+
+// validateHTTPSpec compiles the filter and transform of a request spec
+func validateHTTPSpec(path *field.Path, spec *httpv1alpha1.HTTPSpec) field.ErrorList {
+	var allErrs field.ErrorList
+	if spec.Filter != "" {
+		if _, err := util.CompileItemFilter(spec.Filter); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("filter"), spec.Filter, err.Error()))
+		}
+	}
+	if spec.Transform != "" {
+		if _, err := util.CompileTransform(spec.Transform); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Child("transform"), spec.Transform, err.Error()))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,57 @@
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1"
+)
+
+func TestHTTPQueryResourceCustomValidator(t *testing.T) {
+	validator := &HTTPQueryResourceCustomValidator{}
+	newResource := func(http httpv1alpha1.HTTPSpec, sources ...httpv1alpha1.HTTPSourceSpec) *httpv1alpha1.HTTPQueryResource {
+		http.URL = "https://api.example.com/users"
+		return &httpv1alpha1.HTTPQueryResource{
+			ObjectMeta: metav1.ObjectMeta{Name: "users", Namespace: "default"},
+			Spec:       httpv1alpha1.HTTPQueryResourceSpec{HTTP: http, Sources: sources},
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		resource := newResource(httpv1alpha1.HTTPSpec{
+			Filter:    `item.status == "active" && item.region in ["eu"]`,
+			Transform: `[.users[] | {id, name: .profile.name}]`,
+		})
+		_, err := validator.ValidateCreate(context.Background(), resource)
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		resource := newResource(httpv1alpha1.HTTPSpec{Filter: `item.status ==`}, httpv1alpha1.HTTPSourceSpec{
+			Name:     "teams",
+			HTTPSpec: httpv1alpha1.HTTPSpec{URL: "https://api.example.com/teams", Transform: `.teams[] |`},
+		})
+		_, err := validator.ValidateUpdate(context.Background(), resource, resource)
+		require.Error(t, err)
+		assert.True(t, apierrors.IsInvalid(err))
+
+		statusErr := err.(*apierrors.StatusError)
+		causes := statusErr.Status().Details.Causes
+		require.Len(t, causes, 2)
+		assert.Equal(t, "spec.http.filter", causes[0].Field)
+		assert.Contains(t, causes[0].Message, "invalid CEL expression")
+		assert.Equal(t, "spec.sources[0].transform", causes[1].Field)
+		assert.Contains(t, causes[1].Message, "invalid jq program: unexpected EOF at offset 10")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		resource := newResource(httpv1alpha1.HTTPSpec{Filter: `item.status ==`})
+		_, err := validator.ValidateDelete(context.Background(), resource)
+		assert.NoError(t, err)
+	})
+}
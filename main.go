@@ -6,8 +6,10 @@ import (
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -20,12 +22,14 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	webhookserver "sigs.k8s.io/controller-runtime/pkg/webhook" // Corrected import path
 
 	httpv1alpha1 "github.com/konnektr-io/http-query-operator/api/v1alpha1" // Adjust import path
 	"github.com/konnektr-io/http-query-operator/internal/controller"       // Adjust import path
 	"github.com/konnektr-io/http-query-operator/internal/util"
+	webhookv1alpha1 "github.com/konnektr-io/http-query-operator/internal/webhook/v1alpha1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -87,6 +91,11 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var httpTimeout time.Duration
+	var httpMaxResponseBytes int64
+	var webhookAddr string
+	var templateValueLookups bool
+	var enableAdmissionWebhooks bool
 
 	// Set gvkPattern default from env, allow override by flag
 	gvkPattern = os.Getenv("GVK_PATTERN")
@@ -101,6 +110,19 @@ func main() {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&httpTimeout, "http-timeout", util.DefaultRequestTimeout,
+		"Default timeout of a single HTTP request attempt. Can be overridden per HTTPQueryResource.")
+	flag.Int64Var(&httpMaxResponseBytes, "http-max-response-bytes", util.DefaultMaxResponseBytes,
+		"Default maximum size in bytes of an HTTP response body. Can be overridden per HTTPQueryResource.")
+	flag.StringVar(&webhookAddr, "webhook-bind-address", "",
+		"The address the webhook receiver binds to, e.g. ':8082'. Webhook calls to /webhooks/<namespace>/<name> "+
+			"reconcile the HTTPQueryResource immediately. Disabled if empty.")
+	flag.BoolVar(&templateValueLookups, "template-value-lookups", false,
+		"If set, resource templates can read ConfigMap and Secret values in the namespace of their HTTPQueryResource "+
+			"with configMapValue and secretValue, and Secrets with lookup.")
+	flag.BoolVar(&enableAdmissionWebhooks, "enable-admission-webhooks", false,
+		"If set, the validating admission webhook of HTTPQueryResources is served. Requires a serving certificate "+
+			"and the ValidatingWebhookConfiguration in config/webhook.")
 	opts := zap.Options{
 		Development: true, // Use true for more verbose logs during development
 	}
@@ -155,18 +177,64 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Share a single REST client so that connections and OAuth2 tokens are reused across reconciliations
+	restClient := util.NewRESTClientWithOptions(util.RESTClientOptions{
+		Timeout:          httpTimeout,
+		MaxResponseBytes: httpMaxResponseBytes,
+	})
+
+	// Serve the webhook paths of HTTPQueryResources if enabled. Webhook calls are only served by the
+	// leader, as the HTTPQueryResource controller only runs there.
+	var webhookReceiver *controller.WebhookReceiver
+	if webhookAddr != "" {
+		webhookReceiver = controller.NewWebhookReceiver(mgr.GetClient(), ctrl.Log.WithName("webhooks"))
+		webhookReceiver.MaxBodyBytes = httpMaxResponseBytes
+		if err := mgr.Add(&manager.Server{
+			Name:                "webhook-receiver",
+			Server:              &http.Server{Addr: webhookAddr, Handler: webhookReceiver, ReadHeaderTimeout: 10 * time.Second},
+			OnlyServeWhenLeader: true,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up webhook receiver")
+			os.Exit(1)
+		}
+	}
+
 	if err = (&controller.HTTPQueryResourceReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 		Log:    ctrl.Log.WithName("controllers").WithName("HTTPQueryResource"),
 		HTTPClientFactory: func(ctx context.Context) (util.HTTPClient, error) {
-			return util.NewRESTClient(), nil
+			return restClient, nil
 		},
 		OwnedGVKs: registeredGVKs,
+		TemplateProcessor: &util.TemplateProcessor{
+			Reader:            mgr.GetClient(),
+			LookupGVKs:        registeredGVKs,
+			AllowValueLookups: templateValueLookups,
+		},
+		Recorder: mgr.GetEventRecorderFor(controller.ControllerName),
+		Webhooks: webhookReceiver,
 	}).SetupWithManagerAndGVKs(mgr, registeredGVKs); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HTTPQueryResource")
 		os.Exit(1)
 	}
+	for _, clusterScoped := range []bool{false, true} {
+		if err = (&controller.HTTPEndpointReconciler{
+			Client:        mgr.GetClient(),
+			Log:           ctrl.Log.WithName("controllers").WithName("HTTPEndpoint"),
+			Prober:        restClient,
+			ClusterScoped: clusterScoped,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "HTTPEndpoint", "clusterScoped", clusterScoped)
+			os.Exit(1)
+		}
+	}
+	if enableAdmissionWebhooks {
+		if err = webhookv1alpha1.SetupHTTPQueryResourceWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HTTPQueryResource")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {